@@ -0,0 +1,109 @@
+package dgc
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CommandScope represents the places a command may be used in
+type CommandScope int
+
+// The scopes a command may be restricted to
+const (
+	ScopeAny CommandScope = iota
+	ScopeGuild
+	ScopeDirectMessage
+)
+
+var (
+	// ErrCommandDisabled is the reason used if a command is disabled in the current channel
+	ErrCommandDisabled = errors.New("the command is disabled in this channel")
+
+	// ErrGuildOnly is the reason used if a guild command is used outside of a guild
+	ErrGuildOnly = errors.New("the command can only be used inside a server")
+
+	// ErrDirectMessageOnly is the reason used if a direct message command is used inside a guild
+	ErrDirectMessageOnly = errors.New("the command can only be used in direct messages")
+
+	// ErrMissingPermissions is the reason used if the user lacks the permissions required by a command
+	ErrMissingPermissions = errors.New("you lack the permissions required to use the command")
+
+	// ErrMissingRoles is the reason used if the user has none of the roles required by a command
+	ErrMissingRoles = errors.New("you lack the roles required to use the command")
+)
+
+// commandPath returns the space-separated primary names of the given command chain
+func commandPath(chain []*Command) string {
+	names := make([]string, len(chain))
+	for index, command := range chain {
+		names[index] = command.Name
+	}
+	return strings.Join(names, " ")
+}
+
+// checkAvailability checks whether or not the last command of the given chain may be executed by the author of the given message.
+// The scopes and guards of all the parent commands apply too. If the command is unavailable, the reason is returned.
+func (router *Router) checkAvailability(session *discordgo.Session, message *discordgo.Message, chain []*Command) error {
+	// Check if the command is disabled in the current channel
+	if router.CommandSettings != nil && message.GuildID != "" {
+		if !router.CommandSettings.IsCommandEnabled(message.GuildID, message.ChannelID, commandPath(chain)) {
+			return ErrCommandDisabled
+		}
+	}
+
+	// Check the scopes and guards of the whole chain
+	for _, command := range chain {
+		// Check the scope of the command
+		switch {
+		case command.Scope == ScopeGuild && message.GuildID == "":
+			return ErrGuildOnly
+		case command.Scope == ScopeDirectMessage && message.GuildID != "":
+			return ErrDirectMessageOnly
+		}
+
+		// Permissions and roles only exist inside guilds
+		if message.GuildID == "" {
+			continue
+		}
+
+		// Check if the user has the required permissions
+		if command.Permissions != 0 {
+			permissions, err := session.UserChannelPermissions(message.Author.ID, message.ChannelID)
+			if err != nil || permissions&command.Permissions != command.Permissions {
+				return ErrMissingPermissions
+			}
+		}
+
+		// Check if the user has one of the required roles
+		if len(command.Roles) > 0 && !memberHasAnyRole(session, message, command.Roles) {
+			return ErrMissingRoles
+		}
+	}
+	return nil
+}
+
+// memberHasAnyRole checks whether or not the author of the given message has at least one of the given roles
+func memberHasAnyRole(session *discordgo.Session, message *discordgo.Message, roles []string) bool {
+	// Retrieve the member of the author
+	member := message.Member
+	if member == nil {
+		var err error
+		member, err = session.State.Member(message.GuildID, message.Author.ID)
+		if err != nil {
+			member, err = session.GuildMember(message.GuildID, message.Author.ID)
+			if err != nil {
+				return false
+			}
+		}
+	}
+
+	// Check the roles of the member
+	for _, role := range member.Roles {
+		if stringArrayContains(roles, role, false) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+// dgc-docs exports the command documentation of a router built in a user-supplied package.
+// It has to be executed inside the module containing that package, for example:
+//
+//	go run github.com/lus/dgc/cmd/dgc-docs -pkg github.com/you/bot/commands -func NewRouter -format html -out commands.html
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"text/template"
+)
+
+// regexIdentifier defines the regex the name of the router function has to match
+var regexIdentifier = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+
+// runnerTemplate defines the program that gets generated to build the router and export its documentation
+var runnerTemplate = template.Must(template.New("runner").Parse(`package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lus/dgc"
+	target {{printf "%q" .Package}}
+)
+
+func main() {
+	var router *dgc.Router = target.{{.Function}}()
+	if err := router.ExportDocs(os.Stdout, dgc.DocsFormat({{printf "%q" .Format}})); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+func main() {
+	// Parse the command line flags
+	pkg := flag.String("pkg", "", "the import path of the package building the router")
+	function := flag.String("func", "Router", "the exported function of the package returning the *dgc.Router")
+	format := flag.String("format", "markdown", "the format to export (markdown, json or html)")
+	out := flag.String("out", "", "the file to write the documentation to (defaults to stdout)")
+	flag.Parse()
+
+	if *pkg == "" {
+		fmt.Fprintln(os.Stderr, "the -pkg flag is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if !regexIdentifier.MatchString(*function) {
+		fmt.Fprintln(os.Stderr, "the -func flag has to be the name of an exported function")
+		os.Exit(2)
+	}
+
+	if err := run(*pkg, *function, *format, *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run generates a temporary program inside the current module that builds the router and exports its documentation
+func run(pkg, function, format, out string) error {
+	// Create the temporary directory inside the current module so the package can be resolved
+	directory, err := ioutil.TempDir(".", ".dgc-docs-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(directory)
+
+	// Generate the program
+	file, err := os.Create(filepath.Join(directory, "main.go"))
+	if err != nil {
+		return err
+	}
+	err = runnerTemplate.Execute(file, struct {
+		Package  string
+		Function string
+		Format   string
+	}{
+		Package:  pkg,
+		Function: function,
+		Format:   format,
+	})
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	// Define the output of the program
+	output := os.Stdout
+	if out != "" {
+		output, err = os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+	}
+
+	// Run the program
+	command := exec.Command("go", "run", filepath.Join(directory, "main.go"))
+	command.Stdout = output
+	command.Stderr = os.Stderr
+	return command.Run()
+}
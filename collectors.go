@@ -0,0 +1,118 @@
+package dgc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CollectReactions collects the reactions added to the message with the given ID until the maximum amount got collected,
+// the timeout expired or the context of the command got cancelled.
+// A nil filter accepts every reaction, a maximum of 0 collects reactions until the timeout expired.
+func (ctx *Ctx) CollectReactions(messageID string, filter func(*discordgo.MessageReactionAdd) bool, max int, timeout time.Duration) []*discordgo.MessageReactionAdd {
+	// Define useful variables
+	var reactions []*discordgo.MessageReactionAdd
+	collector := newCollector(max)
+
+	// Collect the reactions
+	bus := ctx.Router.eventBus()
+	subscription := bus.SubscribeReactions(ctx.Session, messageID, func(_ *discordgo.Session, event *discordgo.MessageReactionAdd) {
+		if filter != nil && !filter(event) {
+			return
+		}
+		collector.collect(func() {
+			reactions = append(reactions, event)
+		})
+	})
+	collector.wait(ctx.Context(), timeout)
+	bus.Unsubscribe(subscription)
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	return reactions
+}
+
+// CollectMessages collects the messages matching the given filter until the maximum amount got collected,
+// the timeout expired or the context of the command got cancelled.
+// A nil filter accepts every message sent in the current channel by users who aren't bots, a maximum of 0 collects messages until the timeout expired.
+// Unlike prompt answers, collected messages are still handled as commands.
+func (ctx *Ctx) CollectMessages(filter func(*discordgo.Message) bool, max int, timeout time.Duration) []*discordgo.Message {
+	// Define the default filter
+	if filter == nil {
+		filter = func(message *discordgo.Message) bool {
+			if message.Author == nil || message.Author.Bot || message.Author.ID == ctx.Session.State.User.ID {
+				return false
+			}
+			return message.ChannelID == ctx.Event.ChannelID
+		}
+	}
+
+	// Define useful variables
+	var messages []*discordgo.Message
+	collector := newCollector(max)
+
+	// Collect the messages
+	bus := ctx.Router.eventBus()
+	subscription := bus.SubscribeMessages(func(message *discordgo.Message) bool {
+		if filter(message) {
+			collector.collect(func() {
+				messages = append(messages, message)
+			})
+		}
+		return false
+	})
+	collector.wait(ctx.Context(), timeout)
+	bus.Unsubscribe(subscription)
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	return messages
+}
+
+// collector keeps track of the amount of events collected by a collector
+type collector struct {
+	mutex     sync.Mutex
+	max       int
+	collected int
+	done      chan struct{}
+}
+
+// newCollector creates a new collector collecting the given maximum amount of events
+func newCollector(max int) *collector {
+	return &collector{
+		max:  max,
+		done: make(chan struct{}),
+	}
+}
+
+// collect calls the given function if the maximum amount of events hasn't been collected yet
+func (collector *collector) collect(add func()) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	if collector.max > 0 && collector.collected >= collector.max {
+		return
+	}
+	add()
+	collector.collected++
+	if collector.collected == collector.max {
+		close(collector.done)
+	}
+}
+
+// wait waits until the maximum amount of events got collected, the timeout expired or the given context got cancelled
+func (collector *collector) wait(ctx context.Context, timeout time.Duration) {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case <-collector.done:
+	case <-expired:
+	case <-ctx.Done():
+	}
+}
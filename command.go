@@ -1,21 +1,36 @@
 package dgc
 
 import (
+	"context"
 	"strings"
+	"time"
 )
 
+// DefaultCategory defines the category of commands that don't define one
+const DefaultCategory = "General"
+
 // Command represents a simple command
 type Command struct {
-	Name        string
-	Aliases     []string
-	Description string
-	Usage       string
-	Example     string
-	Flags       []string
-	IgnoreCase  bool
-	SubCommands []*Command
-	RateLimiter RateLimiter
-	Handler     ExecutionHandler
+	Name           string
+	Aliases        []string
+	Category       string
+	Description    string
+	Usage          string
+	Example        string
+	ArgumentSpecs  []*ArgumentSpec
+	Flags          []string
+	IgnoreCase     bool
+	Hidden         bool
+	Scope          CommandScope
+	Permissions    int
+	Roles          []string
+	Typing         bool
+	Timeout        time.Duration
+	MaxConcurrency int
+	SingleFlight   SingleFlightMode
+	SubCommands    []*Command
+	RateLimiter    RateLimiter
+	Handler        ExecutionHandler
 }
 
 // GetSubCmd returns the sub command with the given name if it exists
@@ -35,6 +50,14 @@ func (command *Command) GetSubCmd(name string) *Command {
 	return nil
 }
 
+// categoryName returns the category of the command or the default category if it doesn't define one
+func (command *Command) categoryName() string {
+	if command.Category == "" {
+		return DefaultCategory
+	}
+	return command.Category
+}
+
 // NotifyRateLimiter notifies the rate limiter about a new execution and returns false if the user is being rate limited
 func (command *Command) NotifyRateLimiter(ctx *Ctx) bool {
 	if command.RateLimiter == nil {
@@ -64,17 +87,96 @@ func (command *Command) trigger(ctx *Ctx) {
 				CustomObjects: ctx.CustomObjects,
 				Router:        ctx.Router,
 				Command:       subCommand,
+				commandChain:  append(append([]*Command{}, ctx.commandChain...), subCommand),
+				reexecution:   ctx.reexecution,
+				context:       ctx.context,
 			})
 			return
 		}
 	}
 
-	// Prepare all middlewares
+	// Check if the command is available to the user
+	if reason := ctx.Router.checkAvailability(ctx.Session, ctx.Event.Message, ctx.commandChain); reason != nil {
+		ctx.CustomObjects.Set("dgc_unavailableReason", reason)
+		handler := ctx.Router.UnavailableHandler
+		if reason == ErrCommandDisabled {
+			handler = ctx.Router.DisabledHandler
+		}
+		switch {
+		case handler != nil:
+			handler(ctx)
+		case reason != ErrCommandDisabled:
+			// Tell the user why the command is unavailable if no handler was defined
+			ctx.RespondText(formatUnavailableReason(reason))
+		}
+		return
+	}
+
+	// Prepare all middlewares, the ones of the module the command belongs to wrap the handler first
 	nextHandler := command.Handler
+	for _, middleware := range ctx.Router.moduleMiddlewares(ctx.commandChain[0]) {
+		nextHandler = middleware(nextHandler)
+	}
 	for _, middleware := range ctx.Router.Middlewares {
 		nextHandler = middleware(nextHandler)
 	}
 
-	// Run all middlewares
-	nextHandler(ctx)
+	// Prevent the author from running the command several times at once and limit its concurrent executions
+	ctx.Router.enterInFlightGate(ctx, command, func(leaveInFlight func()) {
+		ctx.Router.enterConcurrencyGate(ctx, command, func(leave func()) {
+			defer leaveInFlight()
+			defer leave()
+
+			// Show the typing indicator while the command is running
+			if command.Typing {
+				stopTyping := ctx.StartTyping()
+				defer stopTyping()
+			}
+
+			// Run all middlewares
+			command.execute(ctx, nextHandler)
+		}, leaveInFlight)
+	})
+}
+
+// execute runs the given handler using a context that expires after the timeout of the command.
+// If the timeout expires, the timeout handler of the router is called, or the user gets told if there is none, while the handler keeps running.
+func (command *Command) execute(ctx *Ctx, handler ExecutionHandler) {
+	// Run the handler directly if the command doesn't time out
+	var cancel context.CancelFunc
+	if command.Timeout <= 0 {
+		ctx.context, cancel = context.WithCancel(ctx.Context())
+		defer cancel()
+		handler(ctx)
+		return
+	}
+
+	// Run the handler and wait for it to finish or to time out
+	ctx.context, cancel = context.WithTimeout(ctx.Context(), command.Timeout)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler(ctx)
+	}()
+	select {
+	case <-done:
+		return
+	case <-ctx.context.Done():
+	}
+
+	// Notify the user if the handler timed out and wait for it to return
+	select {
+	case <-done:
+		return
+	default:
+	}
+	if ctx.context.Err() == context.DeadlineExceeded {
+		if ctx.Router.TimeoutHandler != nil {
+			ctx.Router.TimeoutHandler(ctx)
+		} else {
+			ctx.RespondText("The command took too long and got cancelled.")
+		}
+	}
+	<-done
 }
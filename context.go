@@ -1,6 +1,15 @@
 package dgc
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
 
 // Ctx represents the context for a command event
 type Ctx struct {
@@ -10,28 +19,193 @@ type Ctx struct {
 	CustomObjects *ObjectsMap
 	Router        *Router
 	Command       *Command
+	commandChain  []*Command
+	reexecution   *reexecutionState
+	context       context.Context
+}
+
+// Context returns the context of the command execution.
+// It is cancelled once the command returned, exceeded its timeout or the router shut down.
+func (ctx *Ctx) Context() context.Context {
+	if ctx.context == nil {
+		return ctx.Router.rootContext()
+	}
+	return ctx.context
 }
 
 // ExecutionHandler represents a handler for a context execution
 type ExecutionHandler func(*Ctx)
 
+// Response represents a complex response message
+type Response struct {
+	Content         string
+	Embed           *discordgo.MessageEmbed
+	Files           []*discordgo.File
+	Reply           bool
+	DirectMessage   bool
+	DeleteAfter     time.Duration
+	AllowedMentions *AllowedMentions
+}
+
+// typingInterval defines the interval the typing indicator gets refreshed in
+const typingInterval = 8 * time.Second
+
+// Respond responds with the given complex response message.
+// If the response should be sent as a direct message but the author's direct messages are closed, it gets sent to the current channel instead.
+func (ctx *Ctx) Respond(response *Response) (*discordgo.Message, error) {
+	// Define the message to send
+	data := &messageSend{
+		Content:         response.Content,
+		Embed:           response.Embed,
+		AllowedMentions: response.AllowedMentions,
+		Files:           response.Files,
+	}
+	if data.AllowedMentions == nil {
+		data.AllowedMentions = ctx.Router.AllowedMentions
+	}
+
+	// Try to send the message to the author's direct messages
+	var message *discordgo.Message
+	var err error
+	if response.DirectMessage && ctx.Event.GuildID != "" {
+		// Read the files that can't be rewound into memory so they can be sent again if the attempt fails
+		files, readErr := rewindableFiles(data.Files)
+		if readErr != nil {
+			return nil, readErr
+		}
+		data.Files = files
+
+		channel, channelErr := ctx.Session.UserChannelCreate(ctx.Event.Author.ID)
+		if channelErr == nil {
+			message, err = ctx.send(channel.ID, data)
+		}
+	}
+
+	// Send the message to the current channel
+	if message == nil {
+		// Rewind the files which may have been read by a failed direct message attempt
+		for _, file := range data.Files {
+			if seeker, ok := file.Reader.(io.Seeker); ok {
+				seeker.Seek(0, io.SeekStart)
+			}
+		}
+
+		if response.Reply {
+			data.MessageReference = &messageReference{
+				MessageID: ctx.Event.ID,
+				ChannelID: ctx.Event.ChannelID,
+				GuildID:   ctx.Event.GuildID,
+			}
+		}
+		message, err = ctx.send(ctx.Event.ChannelID, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Delete the message after the given duration
+	if response.DeleteAfter > 0 {
+		session := ctx.Session
+		time.AfterFunc(response.DeleteAfter, func() {
+			session.ChannelMessageDelete(message.ChannelID, message.ID)
+		})
+	}
+	return message, nil
+}
+
+// rewindableFiles returns copies of the given files whose readers can be rewound.
+// Readers that don't implement io.Seeker are read into memory.
+func rewindableFiles(files []*discordgo.File) ([]*discordgo.File, error) {
+	rewindable := make([]*discordgo.File, len(files))
+	for index, file := range files {
+		copied := *file
+		if _, ok := file.Reader.(io.Seeker); !ok && file.Reader != nil {
+			content, err := ioutil.ReadAll(file.Reader)
+			if err != nil {
+				return nil, err
+			}
+			copied.Reader = bytes.NewReader(content)
+		}
+		rewindable[index] = &copied
+	}
+	return rewindable, nil
+}
+
 // RespondText responds with the given text message
 func (ctx *Ctx) RespondText(text string) error {
-	_, err := ctx.Session.ChannelMessageSend(ctx.Event.ChannelID, text)
+	_, err := ctx.Respond(&Response{
+		Content: text,
+	})
 	return err
 }
 
 // RespondEmbed responds with the given embed message
 func (ctx *Ctx) RespondEmbed(embed *discordgo.MessageEmbed) error {
-	_, err := ctx.Session.ChannelMessageSendEmbed(ctx.Event.ChannelID, embed)
+	_, err := ctx.Respond(&Response{
+		Embed: embed,
+	})
 	return err
 }
 
 // RespondTextEmbed responds with the given text and embed message
 func (ctx *Ctx) RespondTextEmbed(text string, embed *discordgo.MessageEmbed) error {
-	_, err := ctx.Session.ChannelMessageSendComplex(ctx.Event.ChannelID, &discordgo.MessageSend{
+	_, err := ctx.Respond(&Response{
 		Content: text,
 		Embed:   embed,
 	})
 	return err
 }
+
+// RespondReply responds with the given text message referencing the invoking message
+func (ctx *Ctx) RespondReply(text string) (*discordgo.Message, error) {
+	return ctx.Respond(&Response{
+		Content: text,
+		Reply:   true,
+	})
+}
+
+// RespondFile responds with the given text message and a file read from the given reader
+func (ctx *Ctx) RespondFile(text, fileName string, reader io.Reader) (*discordgo.Message, error) {
+	return ctx.Respond(&Response{
+		Content: text,
+		Files: []*discordgo.File{
+			{
+				Name:   fileName,
+				Reader: reader,
+			},
+		},
+	})
+}
+
+// RespondDM responds with the given text message in the author's direct messages or in the current channel if they are closed
+func (ctx *Ctx) RespondDM(text string) (*discordgo.Message, error) {
+	return ctx.Respond(&Response{
+		Content:       text,
+		DirectMessage: true,
+	})
+}
+
+// StartTyping shows the typing indicator in the current channel until the returned function gets called
+func (ctx *Ctx) StartTyping() func() {
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(typingInterval)
+		defer ticker.Stop()
+		for {
+			ctx.Session.ChannelTyping(ctx.Event.ChannelID)
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	// Make sure the channel gets closed only once
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+		})
+	}
+}
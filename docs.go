@@ -0,0 +1,371 @@
+package dgc
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DocsFormat represents a format the command documentation may be exported in
+type DocsFormat string
+
+// The formats the command documentation may be exported in
+const (
+	DocsFormatMarkdown DocsFormat = "markdown"
+	DocsFormatJSON     DocsFormat = "json"
+	DocsFormatHTML     DocsFormat = "html"
+)
+
+// ArgumentSpec represents the documentation of a single argument of a command
+type ArgumentSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Optional    bool   `json:"optional"`
+}
+
+// CommandDoc represents the documentation of a single command
+type CommandDoc struct {
+	Name        string          `json:"name"`
+	Path        string          `json:"path"`
+	Aliases     []string        `json:"aliases,omitempty"`
+	Category    string          `json:"category"`
+	Description string          `json:"description"`
+	Usage       string          `json:"usage"`
+	Example     string          `json:"example"`
+	Arguments   []*ArgumentSpec `json:"arguments,omitempty"`
+	Scope       string          `json:"scope"`
+	Permissions []string        `json:"permissions,omitempty"`
+	Roles       []string        `json:"roles,omitempty"`
+	SubCommands []*CommandDoc   `json:"subCommands,omitempty"`
+}
+
+// permissionNames maps the Discord permissions to their readable names
+var permissionNames = []struct {
+	permission int
+	name       string
+}{
+	{discordgo.PermissionCreateInstantInvite, "Create Instant Invite"},
+	{discordgo.PermissionKickMembers, "Kick Members"},
+	{discordgo.PermissionBanMembers, "Ban Members"},
+	{discordgo.PermissionAdministrator, "Administrator"},
+	{discordgo.PermissionManageChannels, "Manage Channels"},
+	{discordgo.PermissionManageServer, "Manage Server"},
+	{discordgo.PermissionAddReactions, "Add Reactions"},
+	{discordgo.PermissionViewAuditLogs, "View Audit Log"},
+	{discordgo.PermissionVoicePrioritySpeaker, "Priority Speaker"},
+	{discordgo.PermissionReadMessages, "Read Messages"},
+	{discordgo.PermissionSendMessages, "Send Messages"},
+	{discordgo.PermissionSendTTSMessages, "Send TTS Messages"},
+	{discordgo.PermissionManageMessages, "Manage Messages"},
+	{discordgo.PermissionEmbedLinks, "Embed Links"},
+	{discordgo.PermissionAttachFiles, "Attach Files"},
+	{discordgo.PermissionReadMessageHistory, "Read Message History"},
+	{discordgo.PermissionMentionEveryone, "Mention Everyone"},
+	{discordgo.PermissionUseExternalEmojis, "Use External Emojis"},
+	{discordgo.PermissionVoiceConnect, "Connect"},
+	{discordgo.PermissionVoiceSpeak, "Speak"},
+	{discordgo.PermissionVoiceMuteMembers, "Mute Members"},
+	{discordgo.PermissionVoiceDeafenMembers, "Deafen Members"},
+	{discordgo.PermissionVoiceMoveMembers, "Move Members"},
+	{discordgo.PermissionVoiceUseVAD, "Use Voice Activity"},
+	{discordgo.PermissionChangeNickname, "Change Nickname"},
+	{discordgo.PermissionManageNicknames, "Manage Nicknames"},
+	{discordgo.PermissionManageRoles, "Manage Roles"},
+	{discordgo.PermissionManageWebhooks, "Manage Webhooks"},
+	{discordgo.PermissionManageEmojis, "Manage Emojis"},
+}
+
+// String returns the readable name of the scope
+func (scope CommandScope) String() string {
+	switch scope {
+	case ScopeGuild:
+		return "guild"
+	case ScopeDirectMessage:
+		return "direct message"
+	default:
+		return "any"
+	}
+}
+
+// Docs walks through all the registered commands and returns their documentation.
+// Hidden commands are left out.
+func (router *Router) Docs() []*CommandDoc {
+	commands := router.commands()
+	docs := make([]*CommandDoc, 0, len(commands))
+	for _, command := range commands {
+		if !command.Hidden {
+			docs = append(docs, buildCommandDoc(command, ""))
+		}
+	}
+	return docs
+}
+
+// buildCommandDoc builds the documentation of the given command and its sub commands
+func buildCommandDoc(command *Command, parentPath string) *CommandDoc {
+	path := strings.TrimSpace(parentPath + " " + command.Name)
+
+	// Define the readable permissions
+	var permissions []string
+	for _, permission := range permissionNames {
+		if command.Permissions&permission.permission != 0 {
+			permissions = append(permissions, permission.name)
+		}
+	}
+
+	// Document the visible sub commands
+	var subCommands []*CommandDoc
+	for _, subCommand := range command.SubCommands {
+		if !subCommand.Hidden {
+			subCommands = append(subCommands, buildCommandDoc(subCommand, path))
+		}
+	}
+
+	return &CommandDoc{
+		Name:        command.Name,
+		Path:        path,
+		Aliases:     command.Aliases,
+		Category:    command.categoryName(),
+		Description: command.Description,
+		Usage:       command.Usage,
+		Example:     command.Example,
+		Arguments:   command.ArgumentSpecs,
+		Scope:       command.Scope.String(),
+		Permissions: permissions,
+		Roles:       command.Roles,
+		SubCommands: subCommands,
+	}
+}
+
+// ExportDocs writes the documentation of all the registered commands in the given format
+func (router *Router) ExportDocs(writer io.Writer, format DocsFormat) error {
+	switch format {
+	case DocsFormatMarkdown:
+		return router.ExportMarkdown(writer)
+	case DocsFormatJSON:
+		return router.ExportJSON(writer)
+	case DocsFormatHTML:
+		return router.ExportHTML(writer)
+	default:
+		return fmt.Errorf("unknown docs format '%s'", format)
+	}
+}
+
+// ExportJSON writes the documentation of all the registered commands as JSON
+func (router *Router) ExportJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(router.Docs())
+}
+
+// ExportMarkdown writes the documentation of all the registered commands as Markdown
+func (router *Router) ExportMarkdown(writer io.Writer) error {
+	builder := &strings.Builder{}
+	builder.WriteString("# Commands\n")
+	for _, category := range groupCommandDocs(router.Docs()) {
+		builder.WriteString("\n## " + category.Name + "\n")
+		for _, doc := range category.Commands {
+			writeMarkdownCommandDoc(builder, router.docsPrefix(), doc, 3)
+		}
+	}
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+// writeMarkdownCommandDoc writes the Markdown documentation of the given command and its sub commands
+func writeMarkdownCommandDoc(builder *strings.Builder, prefix string, doc *CommandDoc, level int) {
+	if level > 6 {
+		level = 6
+	}
+	builder.WriteString("\n" + strings.Repeat("#", level) + " `" + prefix + doc.Path + "`\n\n")
+	if doc.Description != "" {
+		builder.WriteString(escapeMarkdown(doc.Description) + "\n\n")
+	}
+	if len(doc.Aliases) > 0 {
+		builder.WriteString("- **Aliases:** `" + strings.Join(doc.Aliases, "`, `") + "`\n")
+	}
+	if doc.Usage != "" {
+		builder.WriteString("- **Usage:** `" + prefix + doc.Usage + "`\n")
+	}
+	if doc.Example != "" {
+		builder.WriteString("- **Example:** `" + prefix + doc.Example + "`\n")
+	}
+	builder.WriteString("- **Scope:** " + doc.Scope + "\n")
+	if len(doc.Permissions) > 0 {
+		builder.WriteString("- **Permissions:** " + strings.Join(doc.Permissions, ", ") + "\n")
+	}
+	if len(doc.Roles) > 0 {
+		builder.WriteString("- **Roles:** `" + strings.Join(doc.Roles, "`, `") + "`\n")
+	}
+	if len(doc.Arguments) > 0 {
+		builder.WriteString("\n| Argument | Description | Optional |\n|---|---|---|\n")
+		for _, argument := range doc.Arguments {
+			optional := "no"
+			if argument.Optional {
+				optional = "yes"
+			}
+			builder.WriteString("| `" + argument.Name + "` | " + escapeMarkdown(argument.Description) + " | " + optional + " |\n")
+		}
+	}
+	for _, subCommand := range doc.SubCommands {
+		writeMarkdownCommandDoc(builder, prefix, subCommand, level+1)
+	}
+}
+
+// markdownEscaper escapes the characters Markdown would interpret inside of plain text
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\", "`", "\\`", "*", "\\*", "_", "\\_", "~", "\\~", "|", "\\|",
+	"<", "\\<", ">", "\\>", "[", "\\[", "]", "\\]", "#", "\\#",
+)
+
+// escapeMarkdown escapes the given text so it gets rendered as it is
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// htmlDocsTemplate defines the template used to export the documentation as a static HTML page
+var htmlDocsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Commands</title>
+<style>
+body { font-family: sans-serif; max-width: 900px; margin: 0 auto; padding: 1em; color: #2e3338; }
+code { background: #f2f3f5; padding: 0 .2em; border-radius: 3px; }
+.command { border-left: 3px solid #7289da; padding-left: 1em; margin: 1em 0; }
+table { border-collapse: collapse; } td, th { border: 1px solid #ddd; padding: .3em .6em; text-align: left; }
+</style>
+</head>
+<body>
+<h1>Commands</h1>
+{{- range .Categories}}
+<h2>{{.Name}}</h2>
+{{- range .Commands}}{{template "command" .}}{{end}}
+{{- end}}
+</body>
+</html>
+{{define "command"}}
+<div class="command" id="{{.ID}}">
+<h3><code>{{.Prefix}}{{.Doc.Path}}</code></h3>
+{{- if .Doc.Description}}<p>{{.Doc.Description}}</p>{{end}}
+<ul>
+{{- if .Doc.Aliases}}<li><strong>Aliases:</strong> {{range $index, $alias := .Doc.Aliases}}{{if $index}}, {{end}}<code>{{$alias}}</code>{{end}}</li>{{end}}
+{{- if .Doc.Usage}}<li><strong>Usage:</strong> <code>{{.Prefix}}{{.Doc.Usage}}</code></li>{{end}}
+{{- if .Doc.Example}}<li><strong>Example:</strong> <code>{{.Prefix}}{{.Doc.Example}}</code></li>{{end}}
+<li><strong>Scope:</strong> {{.Doc.Scope}}</li>
+{{- if .Doc.Permissions}}<li><strong>Permissions:</strong> {{range $index, $permission := .Doc.Permissions}}{{if $index}}, {{end}}{{$permission}}{{end}}</li>{{end}}
+{{- if .Doc.Roles}}<li><strong>Roles:</strong> {{range $index, $role := .Doc.Roles}}{{if $index}}, {{end}}<code>{{$role}}</code>{{end}}</li>{{end}}
+</ul>
+{{- if .Doc.Arguments}}
+<table>
+<tr><th>Argument</th><th>Description</th><th>Optional</th></tr>
+{{- range .Doc.Arguments}}
+<tr><td><code>{{.Name}}</code></td><td>{{.Description}}</td><td>{{if .Optional}}yes{{else}}no{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- range .SubCommands}}{{template "command" .}}{{end}}
+</div>
+{{- end}}`))
+
+// htmlCommandDoc wraps a command documentation to provide the prefix to the HTML template
+type htmlCommandDoc struct {
+	ID          string
+	Prefix      string
+	Doc         *CommandDoc
+	SubCommands []*htmlCommandDoc
+}
+
+// ExportHTML writes the documentation of all the registered commands as a static HTML page
+func (router *Router) ExportHTML(writer io.Writer) error {
+	// Wrap the documentation of every command
+	prefix := router.docsPrefix()
+	var wrap func(doc *CommandDoc) *htmlCommandDoc
+	wrap = func(doc *CommandDoc) *htmlCommandDoc {
+		wrapped := &htmlCommandDoc{
+			ID:     slugify(doc.Path),
+			Prefix: prefix,
+			Doc:    doc,
+		}
+		for _, subCommand := range doc.SubCommands {
+			wrapped.SubCommands = append(wrapped.SubCommands, wrap(subCommand))
+		}
+		return wrapped
+	}
+
+	// Group the commands by their categories
+	type htmlCategory struct {
+		Name     string
+		Commands []*htmlCommandDoc
+	}
+	var categories []*htmlCategory
+	for _, category := range groupCommandDocs(router.Docs()) {
+		htmlCategory := &htmlCategory{
+			Name: category.Name,
+		}
+		for _, doc := range category.Commands {
+			htmlCategory.Commands = append(htmlCategory.Commands, wrap(doc))
+		}
+		categories = append(categories, htmlCategory)
+	}
+
+	return htmlDocsTemplate.Execute(writer, struct {
+		Categories []*htmlCategory
+	}{
+		Categories: categories,
+	})
+}
+
+// commandDocCategory represents a category of documented commands
+type commandDocCategory struct {
+	Name     string
+	Commands []*CommandDoc
+}
+
+// groupCommandDocs groups the given command documentations by their categories while keeping their order
+func groupCommandDocs(docs []*CommandDoc) []*commandDocCategory {
+	var categories []*commandDocCategory
+	categoryIndexes := make(map[string]int)
+	for _, doc := range docs {
+		index, ok := categoryIndexes[doc.Category]
+		if !ok {
+			index = len(categories)
+			categoryIndexes[doc.Category] = index
+			categories = append(categories, &commandDocCategory{
+				Name: doc.Category,
+			})
+		}
+		categories[index].Commands = append(categories[index].Commands, doc)
+	}
+	return categories
+}
+
+// docsPrefix returns the prefix displayed in the documentation
+func (router *Router) docsPrefix() string {
+	if len(router.Prefixes) == 0 {
+		return ""
+	}
+	return router.Prefixes[0]
+}
+
+// slugify converts the given command path into a string usable as an HTML id
+func slugify(path string) string {
+	builder := &strings.Builder{}
+	separate := false
+	for _, char := range strings.ToLower(path) {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) {
+			separate = true
+			continue
+		}
+		if separate && builder.Len() > 0 {
+			builder.WriteRune('-')
+		}
+		builder.WriteRune(char)
+		separate = false
+	}
+	return "command-" + builder.String()
+}
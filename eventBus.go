@@ -0,0 +1,128 @@
+package dgc
+
+import (
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// eventListener represents a listener for the events published on the event bus.
+// It returns whether or not it consumed the event.
+type eventListener func(session *discordgo.Session, event interface{}) bool
+
+// eventSubscription represents a listener subscribed to the event bus
+type eventSubscription struct {
+	listener eventListener
+}
+
+// eventBus dispatches the events the router receives to all of its subscribers.
+// It is used so collectors, prompts and paginators don't have to register discordgo handlers on their own.
+type eventBus struct {
+	mutex         sync.RWMutex
+	subscriptions map[*eventSubscription]struct{}
+	reactionsOnce sync.Once
+	removeHandler func()
+}
+
+// Publish dispatches the given event to all the subscribers.
+// It returns whether or not the event got consumed by at least one of them.
+func (bus *eventBus) Publish(session *discordgo.Session, event interface{}) bool {
+	// Copy the subscriptions so the listeners are able to unsubscribe
+	bus.mutex.RLock()
+	subscriptions := make([]*eventSubscription, 0, len(bus.subscriptions))
+	for subscription := range bus.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	bus.mutex.RUnlock()
+
+	consumed := false
+	for _, subscription := range subscriptions {
+		if subscription.listener(session, event) {
+			consumed = true
+		}
+	}
+	return consumed
+}
+
+// Subscribe subscribes the given listener to the event bus
+func (bus *eventBus) Subscribe(listener eventListener) *eventSubscription {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+
+	subscription := &eventSubscription{
+		listener: listener,
+	}
+	bus.subscriptions[subscription] = struct{}{}
+	return subscription
+}
+
+// Unsubscribe removes the given subscription from the event bus
+func (bus *eventBus) Unsubscribe(subscription *eventSubscription) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+
+	delete(bus.subscriptions, subscription)
+}
+
+// SubscribeMessages subscribes the given listener to the created messages.
+// Messages consumed by a listener aren't handled as commands.
+func (bus *eventBus) SubscribeMessages(listener func(*discordgo.Message) bool) *eventSubscription {
+	return bus.Subscribe(func(_ *discordgo.Session, event interface{}) bool {
+		if messageCreate, ok := event.(*discordgo.MessageCreate); ok {
+			return listener(messageCreate.Message)
+		}
+		return false
+	})
+}
+
+// ListenReactions registers the handler publishing the reactions added to messages to the given session once
+func (bus *eventBus) ListenReactions(session *discordgo.Session) {
+	bus.reactionsOnce.Do(func() {
+		remove := session.AddHandler(bus.handleReactionAdd)
+
+		bus.mutex.Lock()
+		defer bus.mutex.Unlock()
+		bus.removeHandler = remove
+	})
+}
+
+// Close removes the reaction handler if it has been registered
+func (bus *eventBus) Close() {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+
+	if bus.removeHandler != nil {
+		bus.removeHandler()
+		bus.removeHandler = nil
+	}
+}
+
+// SubscribeReactions subscribes the given listener to the reactions added to the message with the given ID
+func (bus *eventBus) SubscribeReactions(session *discordgo.Session, messageID string, listener func(*discordgo.Session, *discordgo.MessageReactionAdd)) *eventSubscription {
+	bus.ListenReactions(session)
+	return bus.Subscribe(func(session *discordgo.Session, event interface{}) bool {
+		if reactionAdd, ok := event.(*discordgo.MessageReactionAdd); ok && reactionAdd.MessageID == messageID {
+			listener(session, reactionAdd)
+		}
+		return false
+	})
+}
+
+// handleReactionAdd publishes the reactions added to messages
+func (bus *eventBus) handleReactionAdd(session *discordgo.Session, event *discordgo.MessageReactionAdd) {
+	// Ignore the reactions added by the bot itself
+	if event.UserID == session.State.User.ID {
+		return
+	}
+	bus.Publish(session, event)
+}
+
+// eventBus returns the event bus of the router and creates it if needed
+func (router *Router) eventBus() *eventBus {
+	router.eventsOnce.Do(func() {
+		router.events = &eventBus{
+			subscriptions: make(map[*eventSubscription]struct{}),
+		}
+	})
+	return router.events
+}
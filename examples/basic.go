@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -24,13 +25,6 @@ func main() {
 		panic(err)
 	}
 
-	// Wait for the user to cancel the process
-	defer func() {
-		sc := make(chan os.Signal, 1)
-		signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt, os.Kill)
-		<-sc
-	}()
-
 	// Create a dgc router
 	// NOTE: The dgc.Create function makes sure all the maps get initialized
 	router := dgc.Create(&dgc.Router{
@@ -46,6 +40,22 @@ func main() {
 		// We don't want bots to be able to execute our commands
 		BotsAllowed: false,
 
+		// We want commands to be re-executed if the invoking message gets edited shortly after sending it
+		ExecuteOnEdit: true,
+
+		// We want our responses to be deleted if the invoking message gets deleted
+		DeleteResponses: true,
+
+		// Responses are remembered for five minutes by default, but we want to extend this to ten minutes
+		ResponseLifetime: 10 * time.Minute,
+
+		// We don't want user input in our responses to be able to ping everyone or any roles
+		AllowedMentions: &dgc.AllowedMentions{
+			Parse: []dgc.AllowedMentionType{
+				dgc.AllowedMentionTypeUsers,
+			},
+		},
+
 		// We may initialize our commands in here, but we will use the corresponding method later on
 		Commands: []*dgc.Command{},
 
@@ -56,10 +66,42 @@ func main() {
 		PingHandler: func(ctx *dgc.Ctx) {
 			ctx.RespondText("Pong!")
 		},
+
+		// This handler gets called if a command exceeds its timeout
+		TimeoutHandler: func(ctx *dgc.Ctx) {
+			ctx.RespondText("This took too long, sorry!")
+		},
+
+		// We want at most eight commands to run at the same time and up to 100 more to wait for a free worker
+		Workers:   8,
+		QueueSize: 100,
+
+		// If the queue is full, we want to tell the user instead of making them wait
+		OverflowPolicy: dgc.OverflowPolicyReplyBusy,
+		BusyHandler: func(ctx *dgc.Ctx) {
+			ctx.RespondText("I am busy right now, please try again later!")
+		},
+
+		// This handler gets called if a command rejects a second invocation while the first one is still running
+		InFlightHandler: func(ctx *dgc.Ctx) {
+			ctx.RespondText("Please wait until your last command finished!")
+		},
 	})
 
 	// Register the default help command
-	router.RegisterDefaultHelpCommand(session, nil)
+	// NOTE: You may pass options containing a custom renderer, like dgc.NewPlainHelpRenderer() for bots without the permission to send embeds
+	router.RegisterDefaultHelpCommand(session, nil, &dgc.HelpOptions{
+		// We don't want to list commands the user isn't able to use
+		HideUnavailable: true,
+
+		// We want everyone in the channel to be able to page through the help messages for two minutes after the last interaction
+		SharedPaging:   true,
+		SessionTimeout: 2 * time.Minute,
+	})
+
+	// Register the default command settings command so server admins can enable or disable commands
+	// NOTE: This uses an in-memory settings provider if the router has no CommandSettings defined
+	router.RegisterDefaultCommandSettingsCommand(nil)
 
 	// Register a simple middleware that injects a custom object
 	router.RegisterMiddleware(func(next dgc.ExecutionHandler) dgc.ExecutionHandler {
@@ -68,8 +110,11 @@ func main() {
 			ctx.CustomObjects.Set("myObject", 69)
 
 			// You can retrieve the object like this
-			obj := ctx.CustomObjects.MustGet("myObject").(int)
-			fmt.Println(obj)
+			// HINT: The typed getters return an error instead of panicking if the object has another type
+			obj, err := ctx.CustomObjects.GetInt("myObject")
+			if err == nil {
+				fmt.Println(obj)
+			}
 
 			// Call the next execution handler
 			next(ctx)
@@ -86,6 +131,9 @@ func main() {
 			"object",
 		},
 
+		// The default help command groups commands by their categories
+		Category: "Fun",
+
 		// These fields get displayed in the default help messages
 		Description: "Responds with the injected custom object",
 		Usage:       "obj",
@@ -97,6 +145,18 @@ func main() {
 		// We want to ignore the command case
 		IgnoreCase: true,
 
+		// We want to show the typing indicator while the command is running
+		Typing: true,
+
+		// The context returned by ctx.Context() gets cancelled and the timeout handler gets called if the command takes longer than ten seconds
+		Timeout: 10 * time.Second,
+
+		// We want the command to run at most two times at once across the whole bot
+		MaxConcurrency: 2,
+
+		// If a user invokes the command again while it is still running for them, we want the second invocation to wait for the first one
+		SingleFlight: dgc.SingleFlightQueue,
+
 		// You may define sub commands in here
 		SubCommands: []*dgc.Command{},
 
@@ -111,9 +171,22 @@ func main() {
 
 	// Initialize the router
 	router.Initialize(session)
+
+	// Wait for the user to cancel the process
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt, os.Kill)
+	<-sc
+
+	// Give the running commands up to ten seconds to finish before exiting
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := router.Shutdown(ctx); err != nil {
+		fmt.Println(err)
+	}
+	session.Close()
 }
 
 func objCommand(ctx *dgc.Ctx) {
-	// Respond with the just set custom object
-	ctx.RespondText(strconv.Itoa(ctx.CustomObjects.MustGet("myObject").(int)))
+	// Reply to the invoking message with the just set custom object
+	ctx.RespondReply(strconv.Itoa(ctx.CustomObjects.MustGet("myObject").(int)))
 }
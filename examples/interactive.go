@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/lus/dgc"
+)
+
+// This example shows how to ask the user questions while a command is running
+
+func setupCommandHandler(ctx *dgc.Ctx) {
+	// Ask the author for the name of the log channel
+	// HINT: The answer isn't handled as a command and the author may answer 'cancel' to stop the prompt
+	arguments, err := ctx.Prompt("Which channel should be used for the logs?", time.Minute)
+	if err == dgc.ErrPromptCancelled {
+		ctx.RespondText("The setup got cancelled.")
+		return
+	}
+	if err != nil {
+		ctx.RespondText("You took too long to answer.")
+		return
+	}
+	channel := arguments.Raw()
+
+	// Let the author confirm the setup using reactions
+	confirmed, err := ctx.Confirm("Use "+channel+" for the logs?", 30*time.Second)
+	if err != nil || !confirmed {
+		ctx.RespondText("Nothing got changed.")
+		return
+	}
+	ctx.RespondText("The logs will be sent to " + channel + ".")
+}
+
+func pollCommandHandler(ctx *dgc.Ctx) {
+	// Send the poll
+	message, err := ctx.Respond(&dgc.Response{
+		Content: "Pizza or pasta? React with 🍕 or 🍝 in the next minute!",
+	})
+	if err != nil {
+		return
+	}
+	ctx.Session.MessageReactionAdd(message.ChannelID, message.ID, "🍕")
+	ctx.Session.MessageReactionAdd(message.ChannelID, message.ID, "🍝")
+
+	// Collect all the votes for one minute
+	// HINT: The reactions the bot added itself aren't collected
+	votes := ctx.CollectReactions(message.ID, func(event *discordgo.MessageReactionAdd) bool {
+		return event.Emoji.Name == "🍕" || event.Emoji.Name == "🍝"
+	}, 0, time.Minute)
+
+	// Count the votes
+	pizza := 0
+	for _, vote := range votes {
+		if vote.Emoji.Name == "🍕" {
+			pizza++
+		}
+	}
+	ctx.RespondText("🍕 " + strconv.Itoa(pizza) + " : " + strconv.Itoa(len(votes)-pizza) + " 🍝")
+}
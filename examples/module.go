@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lus/dgc"
+)
+
+// This example shows how to group commands, middlewares and state into a module
+
+// moderationModule bundles all the moderation commands
+type moderationModule struct {
+	session *discordgo.Session
+	store   *dgc.ObjectsMap
+}
+
+// Name returns the name of the module
+func (module *moderationModule) Name() string {
+	return "moderation"
+}
+
+// Commands returns the commands of the module
+func (module *moderationModule) Commands() []*dgc.Command {
+	return []*dgc.Command{
+		{
+			Name:        "warn",
+			Description: "Warns a user",
+			Usage:       "warn <user>",
+			Handler: func(ctx *dgc.Ctx) {
+				warnings, _ := module.store.GetInt(ctx.Arguments.Get(0).Raw())
+				module.store.Set(ctx.Arguments.Get(0).Raw(), warnings+1)
+				ctx.RespondText("The user got warned.")
+			},
+		},
+	}
+}
+
+// Middlewares returns the middlewares of the module
+// HINT: These middlewares only wrap the commands of this module
+func (module *moderationModule) Middlewares() []dgc.Middleware {
+	return []dgc.Middleware{}
+}
+
+// Init initializes the module when it gets loaded
+func (module *moderationModule) Init(router *dgc.Router) error {
+	// Every module owns its storage map
+	module.store = router.ModuleStore(module)
+
+	// Event handlers registered like this are removed once the module gets unloaded
+	return router.AddModuleHandler(module, module.session, func(session *discordgo.Session, event *discordgo.GuildMemberAdd) {
+		// Greet new members or check them for previous warnings
+	})
+}
+
+// Close releases the resources of the module when it gets unloaded
+func (module *moderationModule) Close() error {
+	return nil
+}
+
+func loadModerationModule(router *dgc.Router, session *discordgo.Session) {
+	if err := router.LoadModule(&moderationModule{session: session}); err != nil {
+		// The module couldn't be initialized or one of its commands conflicts with an already registered one
+	}
+}
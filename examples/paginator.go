@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/lus/dgc"
+)
+
+// This example shows how to use the integrated reaction-based paginator
+
+func leaderboardCommandHandler(ctx *dgc.Ctx) {
+	// Build the pages we want to display
+	pages := make([]*discordgo.MessageEmbed, 3)
+	for index := range pages {
+		pages[index] = &discordgo.MessageEmbed{
+			Title:       "Leaderboard",
+			Description: "Places " + strconv.Itoa(index*10+1) + " to " + strconv.Itoa(index*10+10),
+		}
+	}
+
+	// Start the paginator
+	// HINT: All the options are optional, so you may also pass nil
+	_, err := ctx.Paginate(pages, &dgc.PaginatorOptions{
+		// We only want the author to be able to switch the pages
+		AuthorOnly: true,
+
+		// The paginator stops listening for reactions if nobody used it for one minute
+		Timeout: time.Minute,
+
+		// We want the page number to be displayed in the footer of every page
+		PageFooters: true,
+
+		// We don't need the controls to jump to the first or last page
+		Emojis: &dgc.PaginatorEmojis{
+			Previous: "⬅️",
+			Stop:     "⏹️",
+			Next:     "➡️",
+		},
+	})
+	if err != nil {
+		// The first page couldn't be sent
+	}
+}
@@ -1,251 +1,193 @@
 package dgc
 
 import (
-	"math"
-	"strconv"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-// RegisterDefaultHelpCommand registers the default help command
-func (router *Router) RegisterDefaultHelpCommand(session *discordgo.Session, rateLimiter RateLimiter) {
-	// Initialize the helo messages storage
-	router.InitializeStorage("dgc_helpMessages")
-
-	// Initialize the reaction add listener
-	session.AddHandler(func(session *discordgo.Session, event *discordgo.MessageReactionAdd) {
-		// Define useful variables
-		channelID := event.ChannelID
-		messageID := event.MessageID
-		userID := event.UserID
-
-		// Check whether or not the reaction was added by the bot itself
-		if event.UserID == session.State.User.ID {
-			return
-		}
-
-		// Check whether or not the message is a help message
-		rawPage, ok := router.Storage["dgc_helpMessages"].Get(channelID + ":" + messageID + ":" + event.UserID)
-		if !ok {
-			return
-		}
-		page := rawPage.(int)
-		if page <= 0 {
-			return
-		}
+// HelpOptions represents the options of the default help command
+type HelpOptions struct {
+	Renderer              HelpRenderer
+	KeepRegistrationOrder bool
+	HideUnavailable       bool
+	SessionTimeout        time.Duration
+	SharedPaging          bool
+}
 
-		// Check which reaction was added
-		reactionName := event.Emoji.Name
-		switch reactionName {
-		case "⬅️":
-			// Update the help message
-			embed, newPage := renderDefaultGeneralHelpEmbed(router, page-1)
-			page = newPage
-			session.ChannelMessageEditEmbed(channelID, messageID, embed)
-
-			// Remove the reaction
-			session.MessageReactionRemove(channelID, messageID, reactionName, userID)
-			break
-		case "❌":
-			// Delete the help message
-			session.ChannelMessageDelete(channelID, messageID)
-			break
-		case "➡️":
-			// Update the help message
-			embed, newPage := renderDefaultGeneralHelpEmbed(router, page+1)
-			page = newPage
-			session.ChannelMessageEditEmbed(channelID, messageID, embed)
-
-			// Remove the reaction
-			session.MessageReactionRemove(channelID, messageID, reactionName, userID)
-			break
-		}
+// RegisterDefaultHelpCommand registers the default help command.
+// If no options or no renderer are given, the default embed-based renderer is used.
+func (router *Router) RegisterDefaultHelpCommand(session *discordgo.Session, rateLimiter RateLimiter, options *HelpOptions) {
+	// Define the default options
+	if options == nil {
+		options = &HelpOptions{}
+	}
+	if options.Renderer == nil {
+		options.Renderer = NewDefaultHelpRenderer()
+	}
 
-		// Update the stores page
-		router.Storage["dgc_helpMessages"].Set(channelID+":"+messageID+":"+event.UserID, page)
-	})
+	// Listen for the reactions used to paginate the help messages
+	router.eventBus().ListenReactions(session)
 
 	// Register the default help command
 	router.RegisterCmd(&Command{
 		Name:        "help",
 		Description: "Lists all the available commands or displays some information about a specific command",
-		Usage:       "help [command name]",
+		Usage:       "help [command or category name | search <term>]",
 		Example:     "help yourCommand",
 		IgnoreCase:  true,
 		RateLimiter: rateLimiter,
-		Handler:     generalHelpCommand,
+		Handler: func(ctx *Ctx) {
+			generalHelpCommand(ctx, options)
+		},
 	})
 }
 
 // generalHelpCommand handles the general help command
-func generalHelpCommand(ctx *Ctx) {
+func generalHelpCommand(ctx *Ctx, options *HelpOptions) {
+	// Check if the user wants to search the commands
+	if ctx.Arguments.Amount() > 1 && equals(ctx.Arguments.Get(0).Raw(), "search", true) {
+		searchHelpCommand(ctx, options)
+		return
+	}
+
 	// Check if the user provided an argument
 	if ctx.Arguments.Amount() > 0 {
-		specificHelpCommand(ctx)
+		specificHelpCommand(ctx, options)
 		return
 	}
 
-	// Define useful variables
-	channelID := ctx.Event.ChannelID
-	session := ctx.Session
+	// Send the categorized command list
+	sendHelpList(ctx, options, buildHelpCategories(ctx, options))
+}
 
-	// Send the general help embed
-	embed, _ := renderDefaultGeneralHelpEmbed(ctx.Router, 1)
-	message, _ := ctx.Session.ChannelMessageSendEmbed(channelID, embed)
+// sendHelpList sends the paginated list of the given categories
+func sendHelpList(ctx *Ctx, options *HelpOptions, categories []*HelpCategory) {
+	// Every page shows at least one command, so there can't be more pages than commands
+	maxPages := 0
+	for _, category := range categories {
+		maxPages += len(category.Commands)
+	}
 
-	// Add the reactions to the message
-	session.MessageReactionAdd(channelID, message.ID, "⬅️")
-	session.MessageReactionAdd(channelID, message.ID, "❌")
-	session.MessageReactionAdd(channelID, message.ID, "➡️")
+	// Render all the pages until the renderer clamps the requested page
+	var pages []*discordgo.MessageSend
+	for page := 1; page == 1 || page <= maxPages; page++ {
+		rendered, renderedPage := options.Renderer.RenderList(ctx.Router, categories, page)
+		if page > 1 && renderedPage != page {
+			break
+		}
+		pages = append(pages, rendered)
+	}
 
-	// Define the message as a help message
-	ctx.Router.Storage["dgc_helpMessages"].Set(channelID+":"+message.ID+":"+ctx.Event.Author.ID, 1)
+	// Send the paginated help message
+	ctx.paginate(pages, &PaginatorOptions{
+		AuthorOnly:   !options.SharedPaging,
+		Timeout:      options.SessionTimeout,
+		DeleteOnStop: true,
+	})
+}
+
+// buildHelpCategories groups the commands visible to the user by their categories.
+// The categories and their commands are sorted alphabetically unless the options say otherwise.
+func buildHelpCategories(ctx *Ctx, options *HelpOptions) []*HelpCategory {
+	// Group the commands while keeping the order of their first appearance
+	var categories []*HelpCategory
+	categoryIndexes := make(map[string]int)
+	for _, command := range ctx.Router.commands() {
+		if !isHelpVisible(ctx, options, []*Command{command}) {
+			continue
+		}
+
+		name := command.categoryName()
+		index, ok := categoryIndexes[strings.ToLower(name)]
+		if !ok {
+			index = len(categories)
+			categoryIndexes[strings.ToLower(name)] = index
+			categories = append(categories, &HelpCategory{
+				Name: name,
+			})
+		}
+		categories[index].Commands = append(categories[index].Commands, command)
+	}
+
+	// Sort the categories and commands alphabetically
+	if !options.KeepRegistrationOrder {
+		sort.SliceStable(categories, func(i, j int) bool {
+			return strings.ToLower(categories[i].Name) < strings.ToLower(categories[j].Name)
+		})
+		for _, category := range categories {
+			commands := category.Commands
+			sort.SliceStable(commands, func(i, j int) bool {
+				return strings.ToLower(commands[i].Name) < strings.ToLower(commands[j].Name)
+			})
+		}
+	}
+	return categories
 }
 
 // specificHelpCommand handles the specific help command
-func specificHelpCommand(ctx *Ctx) {
+func specificHelpCommand(ctx *Ctx, options *HelpOptions) {
 	// Define the command names
 	commandNames := strings.Split(ctx.Arguments.Raw(), " ")
 
-	// Define the command
+	// Define the command chain
 	var command *Command
+	var chain []*Command
 	for index, commandName := range commandNames {
 		if index == 0 {
 			command = ctx.Router.GetCmd(commandName)
-			continue
+		} else {
+			command = command.GetSubCmd(commandName)
 		}
-		if command == nil {
+		if command == nil || command.Hidden {
+			command = nil
 			break
 		}
-		command = command.GetSubCmd(commandName)
+		chain = append(chain, command)
 	}
 
-	// Send the help embed
-	ctx.Session.ChannelMessageSendEmbed(ctx.Event.ChannelID, renderDefaultSpecificHelpEmbed(ctx, command))
-}
-
-// renderDefaultGeneralHelpEmbed renders the general help embed on the given page
-func renderDefaultGeneralHelpEmbed(router *Router, page int) (*discordgo.MessageEmbed, int) {
-	// Define useful variables
-	commands := router.Commands
-	prefix := router.Prefixes[0]
-
-	// Calculate the amount of pages
-	pageAmount := int(math.Ceil(float64(len(commands)) / 5))
-	if page > pageAmount {
-		page = pageAmount
-	}
-	if page <= 0 {
-		page = 1
+	// List the commands of the category with the given name if no command matches
+	if command == nil {
+		for _, category := range buildHelpCategories(ctx, options) {
+			if equals(category.Name, ctx.Arguments.Raw(), true) {
+				sendHelpList(ctx, options, []*HelpCategory{category})
+				return
+			}
+		}
 	}
 
-	// Calculate the slice of commands to display on this page
-	startingIndex := (page - 1) * 5
-	endingIndex := startingIndex + 5
-	if page == pageAmount {
-		endingIndex = len(commands)
-	}
-	displayCommands := commands[startingIndex:endingIndex]
-
-	// Prepare the fields for the embed
-	fields := make([]*discordgo.MessageEmbedField, len(displayCommands))
-	for index, command := range displayCommands {
-		fields[index] = &discordgo.MessageEmbedField{
-			Name:   command.Name,
-			Value:  "`" + command.Description + "`",
-			Inline: false,
-		}
+	// Check if the command is available to the user
+	var unavailableReason error
+	if command != nil {
+		unavailableReason = ctx.Router.checkAvailability(ctx.Session, ctx.Event.Message, chain)
 	}
 
-	// Return the embed and the new page
-	return &discordgo.MessageEmbed{
-		Type:        "rich",
-		Title:       "Command List (Page " + strconv.Itoa(page) + "/" + strconv.Itoa(pageAmount) + ")",
-		Description: "These are all the available commands. Type `" + prefix + "help <command name>` to find out more about a specific command.",
-		Timestamp:   time.Now().Format(time.RFC3339),
-		Color:       0xffff00,
-		Fields:      fields,
-	}, page
+	// Send the help message
+	respondHelpMessage(ctx, options.Renderer.RenderDetail(ctx.Router, command, unavailableReason))
 }
 
-// renderDefaultSpecificHelpEmbed renders the specific help embed of the given command
-func renderDefaultSpecificHelpEmbed(ctx *Ctx, command *Command) *discordgo.MessageEmbed {
-	// Define useful variables
-	prefix := ctx.Router.Prefixes[0]
-
-	// Check if the command is invalid
-	if command == nil {
-		return &discordgo.MessageEmbed{
-			Type:      "rich",
-			Title:     "Error",
-			Timestamp: time.Now().Format(time.RFC3339),
-			Color:     0xff0000,
-			Fields: []*discordgo.MessageEmbedField{
-				{
-					Name:   "Message",
-					Value:  "```The given command doesn't exist. Type `" + prefix + "help` for a list of available commands.```",
-					Inline: false,
-				},
-			},
-		}
-	}
-
-	// Define the sub commands string
-	subCommands := "No sub commands"
-	if len(command.SubCommands) > 0 {
-		subCommandNames := make([]string, len(command.SubCommands))
-		for index, subCommand := range command.SubCommands {
-			subCommandNames[index] = subCommand.Name
-		}
-		subCommands = "`" + strings.Join(subCommandNames, "`, `") + "`"
-	}
+// searchHelpCommand handles the help search command
+func searchHelpCommand(ctx *Ctx, options *HelpOptions) {
+	arguments := ParseArguments(ctx.Arguments.Raw())
+	arguments.Remove(0)
+	term := arguments.Raw()
+	respondHelpMessage(ctx, options.Renderer.RenderSearch(ctx.Router, term, searchCommands(ctx, options, term)))
+}
 
-	// Define the aliases string
-	aliases := "No aliases"
-	if len(command.Aliases) > 0 {
-		aliases = "`" + strings.Join(command.Aliases, "`, `") + "`"
+// isHelpVisible checks whether or not the last command of the given chain should be displayed in the help messages
+func isHelpVisible(ctx *Ctx, options *HelpOptions, chain []*Command) bool {
+	if chain[len(chain)-1].Hidden {
+		return false
 	}
+	return !options.HideUnavailable || ctx.Router.checkAvailability(ctx.Session, ctx.Event.Message, chain) == nil
+}
 
-	// Return the embed
-	return &discordgo.MessageEmbed{
-		Type:        "rich",
-		Title:       "Command Information",
-		Description: "Displaying the information for the `" + command.Name + "` command.",
-		Timestamp:   time.Now().Format(time.RFC3339),
-		Color:       0xffff00,
-		Fields: []*discordgo.MessageEmbedField{
-			{
-				Name:   "Name",
-				Value:  "`" + command.Name + "`",
-				Inline: false,
-			},
-			{
-				Name:   "Sub Commands",
-				Value:  subCommands,
-				Inline: false,
-			},
-			{
-				Name:   "Aliases",
-				Value:  aliases,
-				Inline: false,
-			},
-			{
-				Name:   "Description",
-				Value:  "```" + command.Description + "```",
-				Inline: false,
-			},
-			{
-				Name:   "Usage",
-				Value:  "```" + prefix + command.Usage + "```",
-				Inline: false,
-			},
-			{
-				Name:   "Example",
-				Value:  "```" + prefix + command.Example + "```",
-				Inline: false,
-			},
-		},
-	}
+// respondHelpMessage responds with the given rendered help message
+func respondHelpMessage(ctx *Ctx, message *discordgo.MessageSend) (*discordgo.Message, error) {
+	return ctx.Respond(&Response{
+		Content: message.Content,
+		Embed:   message.Embed,
+	})
 }
@@ -0,0 +1,365 @@
+package dgc
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// HelpRenderer represents a renderer used by the default help command to display its messages
+type HelpRenderer interface {
+	// RenderList renders the given page of the categorized command list and returns the message and the page that actually got rendered
+	RenderList(router *Router, categories []*HelpCategory, page int) (*discordgo.MessageSend, int)
+
+	// RenderDetail renders the information about the given command or an error message if the command is nil.
+	// If the command is unavailable to the user, the reason is given too.
+	RenderDetail(router *Router, command *Command, unavailableReason error) *discordgo.MessageSend
+
+	// RenderSearch renders the ranked results of a search for the given term
+	RenderSearch(router *Router, term string, results []*HelpSearchResult) *discordgo.MessageSend
+}
+
+// HelpCategory represents a category of commands displayed together in the command list
+type HelpCategory struct {
+	Name     string
+	Commands []*Command
+}
+
+// DefaultHelpRenderer represents the default embed-based help renderer
+type DefaultHelpRenderer struct {
+	Title           string
+	Color           int
+	ErrorColor      int
+	CommandsPerPage int
+}
+
+// NewDefaultHelpRenderer creates a new default help renderer
+func NewDefaultHelpRenderer() HelpRenderer {
+	return &DefaultHelpRenderer{
+		Title:           "Command List",
+		Color:           0xffff00,
+		ErrorColor:      0xff0000,
+		CommandsPerPage: 5,
+	}
+}
+
+// RenderList renders the given page of the categorized command list as an embed
+func (renderer *DefaultHelpRenderer) RenderList(router *Router, categories []*HelpCategory, page int) (*discordgo.MessageSend, int) {
+	// Define useful variables
+	prefix := router.Prefixes[0]
+	category, displayCommands, page, pageAmount := paginateCategories(categories, page, renderer.CommandsPerPage)
+
+	// Prepare the fields for the embed
+	fields := make([]*discordgo.MessageEmbedField, len(displayCommands))
+	for index, command := range displayCommands {
+		fields[index] = &discordgo.MessageEmbedField{
+			Name:   command.Name,
+			Value:  "`" + command.Description + "`",
+			Inline: false,
+		}
+	}
+
+	// Return the embed and the new page
+	return &discordgo.MessageSend{
+		Embed: &discordgo.MessageEmbed{
+			Type:        "rich",
+			Title:       renderer.Title + ": " + category + " (Page " + strconv.Itoa(page) + "/" + strconv.Itoa(pageAmount) + ")",
+			Description: "These are all the available commands. Type `" + prefix + "help <command name>` to find out more about a specific command or `" + prefix + "help <category name>` to list the commands of a specific category.",
+			Timestamp:   time.Now().Format(time.RFC3339),
+			Color:       renderer.Color,
+			Fields:      fields,
+		},
+	}, page
+}
+
+// RenderDetail renders the information about the given command as an embed
+func (renderer *DefaultHelpRenderer) RenderDetail(router *Router, command *Command, unavailableReason error) *discordgo.MessageSend {
+	// Define useful variables
+	prefix := router.Prefixes[0]
+
+	// Check if the command is invalid
+	if command == nil {
+		return &discordgo.MessageSend{
+			Embed: &discordgo.MessageEmbed{
+				Type:      "rich",
+				Title:     "Error",
+				Timestamp: time.Now().Format(time.RFC3339),
+				Color:     renderer.ErrorColor,
+				Fields: []*discordgo.MessageEmbedField{
+					{
+						Name:   "Message",
+						Value:  "```The given command doesn't exist. Type `" + prefix + "help` for a list of available commands.```",
+						Inline: false,
+					},
+				},
+			},
+		}
+	}
+
+	// Define the fields of the embed
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "Name",
+			Value:  "`" + command.Name + "`",
+			Inline: false,
+		},
+		{
+			Name:   "Category",
+			Value:  "`" + command.categoryName() + "`",
+			Inline: false,
+		},
+		{
+			Name:   "Sub Commands",
+			Value:  formatSubCommands(command),
+			Inline: false,
+		},
+		{
+			Name:   "Aliases",
+			Value:  formatAliases(command),
+			Inline: false,
+		},
+		{
+			Name:   "Description",
+			Value:  "```" + command.Description + "```",
+			Inline: false,
+		},
+		{
+			Name:   "Usage",
+			Value:  "```" + prefix + command.Usage + "```",
+			Inline: false,
+		},
+		{
+			Name:   "Example",
+			Value:  "```" + prefix + command.Example + "```",
+			Inline: false,
+		},
+	}
+
+	// Tell the user why the command is unavailable
+	if unavailableReason != nil {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Unavailable",
+			Value:  "```" + formatUnavailableReason(unavailableReason) + "```",
+			Inline: false,
+		})
+	}
+
+	// Return the embed
+	return &discordgo.MessageSend{
+		Embed: &discordgo.MessageEmbed{
+			Type:        "rich",
+			Title:       "Command Information",
+			Description: "Displaying the information for the `" + command.Name + "` command.",
+			Timestamp:   time.Now().Format(time.RFC3339),
+			Color:       renderer.Color,
+			Fields:      fields,
+		},
+	}
+}
+
+// RenderSearch renders the ranked results of a search for the given term as an embed
+func (renderer *DefaultHelpRenderer) RenderSearch(router *Router, term string, results []*HelpSearchResult) *discordgo.MessageSend {
+	// Define useful variables
+	prefix := router.Prefixes[0]
+
+	// Check if no command matches the term
+	if len(results) == 0 {
+		return &discordgo.MessageSend{
+			Embed: &discordgo.MessageEmbed{
+				Type:      "rich",
+				Title:     "Search Results",
+				Timestamp: time.Now().Format(time.RFC3339),
+				Color:     renderer.ErrorColor,
+				Fields: []*discordgo.MessageEmbedField{
+					{
+						Name:   "Message",
+						Value:  "```No command matches the given term. Type `" + prefix + "help` for a list of available commands.```",
+						Inline: false,
+					},
+				},
+			},
+		}
+	}
+
+	// Prepare the fields for the embed
+	fields := make([]*discordgo.MessageEmbedField, len(results))
+	for index, result := range results {
+		fields[index] = &discordgo.MessageEmbedField{
+			Name:   prefix + result.Path,
+			Value:  "`" + result.Command.Description + "`",
+			Inline: false,
+		}
+	}
+
+	// Return the embed
+	return &discordgo.MessageSend{
+		Embed: &discordgo.MessageEmbed{
+			Type:        "rich",
+			Title:       "Search Results",
+			Description: "These are the commands matching `" + term + "`. Type `" + prefix + "help <command name>` to find out more about a specific command.",
+			Timestamp:   time.Now().Format(time.RFC3339),
+			Color:       renderer.Color,
+			Fields:      fields,
+		},
+	}
+}
+
+// PlainHelpRenderer represents a help renderer using plain text messages for bots lacking the permission to send embeds
+type PlainHelpRenderer struct {
+	CommandsPerPage int
+}
+
+// NewPlainHelpRenderer creates a new plain text help renderer
+func NewPlainHelpRenderer() HelpRenderer {
+	return &PlainHelpRenderer{
+		CommandsPerPage: 10,
+	}
+}
+
+// RenderList renders the given page of the categorized command list as plain text
+func (renderer *PlainHelpRenderer) RenderList(router *Router, categories []*HelpCategory, page int) (*discordgo.MessageSend, int) {
+	// Define useful variables
+	prefix := router.Prefixes[0]
+	category, displayCommands, page, pageAmount := paginateCategories(categories, page, renderer.CommandsPerPage)
+
+	// Build the message content
+	builder := &strings.Builder{}
+	builder.WriteString("**Command List: " + category + " (Page " + strconv.Itoa(page) + "/" + strconv.Itoa(pageAmount) + ")**\n")
+	builder.WriteString("These are all the available commands. Type `" + prefix + "help <command name>` to find out more about a specific command or `" + prefix + "help <category name>` to list the commands of a specific category.\n")
+	for _, command := range displayCommands {
+		builder.WriteString("\n`" + command.Name + "` - " + command.Description)
+	}
+
+	// Return the message and the new page
+	return &discordgo.MessageSend{
+		Content: builder.String(),
+	}, page
+}
+
+// RenderDetail renders the information about the given command as plain text
+func (renderer *PlainHelpRenderer) RenderDetail(router *Router, command *Command, unavailableReason error) *discordgo.MessageSend {
+	// Define useful variables
+	prefix := router.Prefixes[0]
+
+	// Check if the command is invalid
+	if command == nil {
+		return &discordgo.MessageSend{
+			Content: "The given command doesn't exist. Type `" + prefix + "help` for a list of available commands.",
+		}
+	}
+
+	// Build the message content
+	builder := &strings.Builder{}
+	builder.WriteString("**Command Information: `" + command.Name + "`**\n")
+	builder.WriteString("**Category:** " + command.categoryName() + "\n")
+	builder.WriteString("**Sub Commands:** " + formatSubCommands(command) + "\n")
+	builder.WriteString("**Aliases:** " + formatAliases(command) + "\n")
+	builder.WriteString("**Description:** " + command.Description + "\n")
+	builder.WriteString("**Usage:** `" + prefix + command.Usage + "`\n")
+	builder.WriteString("**Example:** `" + prefix + command.Example + "`")
+	if unavailableReason != nil {
+		builder.WriteString("\n**Unavailable:** " + formatUnavailableReason(unavailableReason))
+	}
+
+	// Return the message
+	return &discordgo.MessageSend{
+		Content: builder.String(),
+	}
+}
+
+// RenderSearch renders the ranked results of a search for the given term as plain text
+func (renderer *PlainHelpRenderer) RenderSearch(router *Router, term string, results []*HelpSearchResult) *discordgo.MessageSend {
+	// Define useful variables
+	prefix := router.Prefixes[0]
+
+	// Check if no command matches the term
+	if len(results) == 0 {
+		return &discordgo.MessageSend{
+			Content: "No command matches the given term. Type `" + prefix + "help` for a list of available commands.",
+		}
+	}
+
+	// Build the message content
+	builder := &strings.Builder{}
+	builder.WriteString("**Search Results**\n")
+	builder.WriteString("These are the commands matching `" + term + "`. Type `" + prefix + "help <command name>` to find out more about a specific command.\n")
+	for _, result := range results {
+		builder.WriteString("\n`" + prefix + result.Path + "` - " + result.Command.Description)
+	}
+
+	// Return the message
+	return &discordgo.MessageSend{
+		Content: builder.String(),
+	}
+}
+
+// paginateCategories returns the name of the category and the commands to display on the given page, the corrected page and the amount of pages.
+// Every page only contains commands of a single category.
+func paginateCategories(categories []*HelpCategory, page, commandsPerPage int) (string, []*Command, int, int) {
+	if commandsPerPage <= 0 {
+		commandsPerPage = 5
+	}
+
+	// Calculate the amount of pages
+	pageAmount := 0
+	for _, category := range categories {
+		pageAmount += int(math.Ceil(float64(len(category.Commands)) / float64(commandsPerPage)))
+	}
+	if pageAmount == 0 {
+		return "", []*Command{}, 1, 1
+	}
+	if page > pageAmount {
+		page = pageAmount
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	// Find the category the page belongs to and calculate the slice of commands to display on this page
+	remaining := page
+	for _, category := range categories {
+		categoryPages := int(math.Ceil(float64(len(category.Commands)) / float64(commandsPerPage)))
+		if remaining > categoryPages {
+			remaining -= categoryPages
+			continue
+		}
+		startingIndex := (remaining - 1) * commandsPerPage
+		endingIndex := startingIndex + commandsPerPage
+		if endingIndex > len(category.Commands) {
+			endingIndex = len(category.Commands)
+		}
+		return category.Name, category.Commands[startingIndex:endingIndex], page, pageAmount
+	}
+	return "", []*Command{}, page, pageAmount
+}
+
+// formatSubCommands formats the names of the visible sub commands of the given command
+func formatSubCommands(command *Command) string {
+	var subCommandNames []string
+	for _, subCommand := range command.SubCommands {
+		if !subCommand.Hidden {
+			subCommandNames = append(subCommandNames, subCommand.Name)
+		}
+	}
+	if len(subCommandNames) == 0 {
+		return "No sub commands"
+	}
+	return "`" + strings.Join(subCommandNames, "`, `") + "`"
+}
+
+// formatUnavailableReason formats the given reason a command is unavailable
+func formatUnavailableReason(reason error) string {
+	message := reason.Error()
+	return strings.ToUpper(message[:1]) + message[1:] + "."
+}
+
+// formatAliases formats the aliases of the given command
+func formatAliases(command *Command) string {
+	if len(command.Aliases) == 0 {
+		return "No aliases"
+	}
+	return "`" + strings.Join(command.Aliases, "`, `") + "`"
+}
@@ -0,0 +1,217 @@
+package dgc
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// helpSearchResultLimit defines the maximum amount of results a help search returns
+	helpSearchResultLimit = 10
+
+	// helpSearchMinimumScore defines the score a command has to reach to be considered a result
+	helpSearchMinimumScore = 20
+
+	// helpSearchMinimumTermLength defines the length a term needs to be matched inside of names, as an abbreviation or in descriptions and usages
+	helpSearchMinimumTermLength = 2
+)
+
+// HelpSearchResult represents a command matching the term of a help search
+type HelpSearchResult struct {
+	Path    string
+	Command *Command
+	Score   float64
+}
+
+// searchCommands searches all the commands and sub commands visible to the user for the given term and returns the best matches
+func searchCommands(ctx *Ctx, options *HelpOptions, term string) []*HelpSearchResult {
+	term = strings.ToLower(strings.TrimSpace(term))
+	var results []*HelpSearchResult
+
+	// Walk through the whole command tree
+	var walk func(chain []*Command)
+	walk = func(chain []*Command) {
+		command := chain[len(chain)-1]
+		if !isHelpVisible(ctx, options, chain) {
+			return
+		}
+		if score := scoreCommand(command, term); score >= helpSearchMinimumScore {
+			results = append(results, &HelpSearchResult{
+				Path:    commandPath(chain),
+				Command: command,
+				Score:   score,
+			})
+		}
+		for _, subCommand := range command.SubCommands {
+			walk(append(append([]*Command{}, chain...), subCommand))
+		}
+	}
+	for _, command := range ctx.Router.commands() {
+		walk([]*Command{command})
+	}
+
+	// Rank the results
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Path < results[j].Path
+	})
+	if len(results) > helpSearchResultLimit {
+		results = results[:helpSearchResultLimit]
+	}
+	return results
+}
+
+// scoreCommand calculates how well the given command matches the given lowercase term
+func scoreCommand(command *Command, term string) float64 {
+	// Names and aliases weigh the most
+	score := 0.0
+	for _, name := range append([]string{command.Name}, command.Aliases...) {
+		score = maxScore(score, scoreName(strings.ToLower(name), term))
+	}
+
+	// Descriptions and usages are matched word by word
+	score = maxScore(score, 0.6*scoreText(strings.ToLower(command.Description), term))
+	score = maxScore(score, 0.5*scoreText(strings.ToLower(command.Usage), term))
+	return score
+}
+
+// scoreName calculates how well the given lowercase name matches the given lowercase term
+func scoreName(name, term string) float64 {
+	switch {
+	case name == term:
+		return 100
+	case strings.HasPrefix(name, term):
+		return 80
+	case utf8.RuneCountInString(term) >= helpSearchMinimumTermLength && strings.Contains(name, term):
+		return 60
+	}
+
+	// Short terms only match names starting with them
+	nameLength, termLength := utf8.RuneCountInString(name), utf8.RuneCountInString(term)
+	if termLength < helpSearchMinimumTermLength {
+		return 0
+	}
+
+	// Allow small typos
+	longest := nameLength
+	if termLength > longest {
+		longest = termLength
+	}
+	similarity := 1 - float64(levenshtein(name, term))/float64(longest)
+	if similarity >= 0.6 {
+		return 70 * similarity
+	}
+
+	// Allow abbreviations starting with the same character like 'tb' for 'tempban'
+	if strings.HasPrefix(name, string([]rune(term)[:1])) && isSubsequence(term, name) {
+		return 30 + 20*float64(termLength)/float64(nameLength)
+	}
+	return 0
+}
+
+// scoreText calculates how well the given lowercase text matches the given lowercase term.
+// Only whole words or word prefixes are matched and terms shorter than the minimum term length are ignored.
+func scoreText(text, term string) float64 {
+	if text == "" || utf8.RuneCountInString(term) < helpSearchMinimumTermLength {
+		return 0
+	}
+	words := strings.Fields(text)
+	for index := range words {
+		words[index] = strings.Trim(words[index], ".,:;!?()[]<>`'\"|")
+	}
+	if strings.Contains(" "+strings.Join(words, " ")+" ", " "+strings.Join(strings.Fields(term), " ")) {
+		return 70
+	}
+
+	// Match every word of the term against the words of the text
+	termWords := strings.Fields(term)
+	total := 0.0
+	for _, termWord := range termWords {
+		best := 0.0
+		for _, word := range words {
+			best = maxScore(best, scoreWord(word, termWord))
+		}
+		total += best
+	}
+	return total / float64(len(termWords))
+}
+
+// scoreWord calculates how well the given lowercase word of a text matches the given lowercase term word
+func scoreWord(word, term string) float64 {
+	switch {
+	case word == term:
+		return 100
+	case utf8.RuneCountInString(term) >= helpSearchMinimumTermLength && strings.HasPrefix(word, term):
+		return 80
+	}
+
+	// Allow small typos
+	longest := utf8.RuneCountInString(word)
+	if termLength := utf8.RuneCountInString(term); termLength > longest {
+		longest = termLength
+	}
+	similarity := 1 - float64(levenshtein(word, term))/float64(longest)
+	if similarity >= 0.6 {
+		return 70 * similarity
+	}
+	return 0
+}
+
+// maxScore returns the greater one of both scores
+func maxScore(score1, score2 float64) float64 {
+	if score1 > score2 {
+		return score1
+	}
+	return score2
+}
+
+// isSubsequence checks whether or not all characters of the given term appear in the given string in the same order
+func isSubsequence(term, str string) bool {
+	termRunes := []rune(term)
+	if len(termRunes) == 0 {
+		return false
+	}
+	index := 0
+	for _, char := range str {
+		if char == termRunes[index] {
+			index++
+			if index == len(termRunes) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// levenshtein calculates the edit distance between both strings
+func levenshtein(str1, str2 string) int {
+	runes1, runes2 := []rune(str1), []rune(str2)
+	previous := make([]int, len(runes2)+1)
+	current := make([]int, len(runes2)+1)
+	for index := range previous {
+		previous[index] = index
+	}
+	for i := 1; i <= len(runes1); i++ {
+		current[0] = i
+		for j := 1; j <= len(runes2); j++ {
+			cost := 1
+			if runes1[i-1] == runes2[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(runes2)]
+}
+
+// minInt returns the smaller one of both integers
+func minInt(int1, int2 int) int {
+	if int1 < int2 {
+		return int1
+	}
+	return int2
+}
@@ -0,0 +1,292 @@
+package dgc
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// The limits Discord applies to messages and embeds
+const (
+	MessageLengthLimit    = 2000
+	EmbedTitleLimit       = 256
+	EmbedDescriptionLimit = 4096
+	EmbedFieldAmountLimit = 25
+	EmbedFieldNameLimit   = 256
+	EmbedFieldValueLimit  = 1024
+	EmbedTotalLimit       = 6000
+)
+
+// codeFence represents the characters opening and closing a big codeblock
+const codeFence = "```"
+
+// LongResponseOptions represents the options used to respond with long text messages
+type LongResponseOptions struct {
+	FileThreshold int
+	FileName      string
+}
+
+// RespondLong responds with the given text message and splits it into several messages if it exceeds the message length limit.
+// If the options define a file threshold and the text exceeds it, the text gets uploaded as a file instead.
+func (ctx *Ctx) RespondLong(text string, options *LongResponseOptions) ([]*discordgo.Message, error) {
+	if options == nil {
+		options = &LongResponseOptions{}
+	}
+
+	// Upload the text as a file if it exceeds the file threshold
+	if options.FileThreshold > 0 && utf8.RuneCountInString(text) > options.FileThreshold {
+		fileName := options.FileName
+		if fileName == "" {
+			fileName = "response.txt"
+		}
+		message, err := ctx.RespondFile("", fileName, strings.NewReader(text))
+		if err != nil {
+			return nil, err
+		}
+		return []*discordgo.Message{message}, nil
+	}
+
+	// Send every part as a separate message
+	parts := SplitMessage(text, MessageLengthLimit)
+	messages := make([]*discordgo.Message, 0, len(parts))
+	for _, part := range parts {
+		message, err := ctx.Respond(&Response{
+			Content: part,
+		})
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// RespondLongEmbed responds with the given embed message and splits it into several embeds if it exceeds the embed limits
+func (ctx *Ctx) RespondLongEmbed(embed *discordgo.MessageEmbed) ([]*discordgo.Message, error) {
+	embeds := SplitEmbed(embed)
+	messages := make([]*discordgo.Message, 0, len(embeds))
+	for _, part := range embeds {
+		message, err := ctx.Respond(&Response{
+			Embed: part,
+		})
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// SplitMessage splits the given text into parts not exceeding the given limit.
+// The text gets split at line boundaries if possible and codeblocks spanning several parts get closed and re-opened.
+// Parts only consisting of whitespace are left out.
+func SplitMessage(text string, limit int) []string {
+	if limit <= 0 {
+		limit = MessageLengthLimit
+	}
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	// Define the state of the splitting process
+	var parts []string
+	current := &strings.Builder{}
+	currentLength := 0
+	hasContent := false
+	openingFence := ""
+
+	// flush finishes the current part and starts a new one, re-opening the current codeblock if needed
+	flush := func() {
+		part := strings.TrimRight(current.String(), "\n")
+		if openingFence != "" {
+			part += "\n" + codeFence
+		}
+		parts = appendPart(parts, part)
+		current.Reset()
+		currentLength = 0
+		hasContent = false
+		if openingFence != "" {
+			current.WriteString(openingFence)
+			currentLength = utf8.RuneCountInString(openingFence)
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		// Check whether or not the line opens or closes a codeblock
+		newOpeningFence := openingFence
+		if strings.Count(line, codeFence)%2 == 1 {
+			if openingFence == "" {
+				newOpeningFence = parseOpeningFence(line)
+			} else {
+				newOpeningFence = ""
+			}
+		}
+
+		// Reserve the space for closing and re-opening a codeblock
+		reserved := 0
+		if openingFence != "" || newOpeningFence != "" {
+			reserved = len(codeFence) + 1
+		}
+		maxLineLength := limit - reserved - utf8.RuneCountInString(newOpeningFence+openingFence) - 1
+		if maxLineLength <= 0 {
+			maxLineLength = 1
+		}
+
+		// Append every piece of the line, splitting it if it is too long itself
+		for _, piece := range splitLongLine(line, maxLineLength) {
+			pieceLength := utf8.RuneCountInString(piece)
+			if currentLength > 0 {
+				pieceLength++
+			}
+			if hasContent && currentLength+pieceLength+reserved > limit {
+				flush()
+				pieceLength = utf8.RuneCountInString(piece)
+				if currentLength > 0 {
+					pieceLength++
+				}
+			}
+			if currentLength > 0 {
+				current.WriteString("\n")
+			}
+			current.WriteString(piece)
+			currentLength += pieceLength
+			hasContent = true
+		}
+		openingFence = newOpeningFence
+	}
+
+	// Add the last part without closing an unclosed codeblock
+	if hasContent {
+		parts = appendPart(parts, strings.TrimRight(current.String(), "\n"))
+	}
+	return parts
+}
+
+// appendPart appends the given part unless it is empty or only consists of whitespace
+func appendPart(parts []string, part string) []string {
+	if strings.TrimSpace(part) == "" {
+		return parts
+	}
+	return append(parts, part)
+}
+
+// parseOpeningFence returns the fence (including the language) opening the codeblock started in the given line
+func parseOpeningFence(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, codeFence) {
+		return codeFence
+	}
+	language := strings.TrimPrefix(trimmed, codeFence)
+	if language == "" || strings.ContainsAny(language, " \t`") {
+		return codeFence
+	}
+	return codeFence + language
+}
+
+// splitLongLine splits the given line into pieces not exceeding the given length, preferably at spaces
+func splitLongLine(line string, length int) []string {
+	runes := []rune(line)
+	if len(runes) <= length {
+		return []string{line}
+	}
+
+	var pieces []string
+	for len(runes) > length {
+		// Find the last space inside the allowed length
+		splitIndex := length
+		for index := length; index > length/2; index-- {
+			if runes[index] == ' ' {
+				splitIndex = index
+				break
+			}
+		}
+		pieces = append(pieces, string(runes[:splitIndex]))
+		runes = runes[splitIndex:]
+		if len(runes) > 0 && runes[0] == ' ' {
+			runes = runes[1:]
+		}
+	}
+	return append(pieces, string(runes))
+}
+
+// SplitEmbed splits the given embed into several embeds not exceeding the embed limits.
+// The title, author and thumbnail are kept on the first embed and the footer, image and timestamp are moved to the last one.
+func SplitEmbed(embed *discordgo.MessageEmbed) []*discordgo.MessageEmbed {
+	// Define the first embed containing the header of the original one
+	newEmbed := func() *discordgo.MessageEmbed {
+		return &discordgo.MessageEmbed{
+			Type:  embed.Type,
+			Color: embed.Color,
+		}
+	}
+	first := newEmbed()
+	first.URL = embed.URL
+	first.Title = truncate(embed.Title, EmbedTitleLimit)
+	first.Author = embed.Author
+	first.Thumbnail = embed.Thumbnail
+	embeds := []*discordgo.MessageEmbed{first}
+	current := first
+
+	// Reserve the space for the footer of the last embed
+	budget := EmbedTotalLimit
+	if embed.Footer != nil {
+		budget -= utf8.RuneCountInString(embed.Footer.Text)
+	}
+
+	// Split the description
+	for index, part := range SplitMessage(embed.Description, EmbedDescriptionLimit) {
+		if index > 0 || embedLength(current)+utf8.RuneCountInString(part) > budget {
+			current = newEmbed()
+			embeds = append(embeds, current)
+		}
+		current.Description = part
+	}
+
+	// Split the fields
+	for _, field := range embed.Fields {
+		name := truncate(field.Name, EmbedFieldNameLimit)
+		for _, value := range SplitMessage(field.Value, EmbedFieldValueLimit) {
+			fieldLength := utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+			if len(current.Fields) >= EmbedFieldAmountLimit || embedLength(current)+fieldLength > budget {
+				current = newEmbed()
+				embeds = append(embeds, current)
+			}
+			current.Fields = append(current.Fields, &discordgo.MessageEmbedField{
+				Name:   name,
+				Value:  value,
+				Inline: field.Inline,
+			})
+		}
+	}
+
+	// Move the footer to the last embed
+	current.Footer = embed.Footer
+	current.Image = embed.Image
+	current.Timestamp = embed.Timestamp
+	return embeds
+}
+
+// embedLength calculates the amount of characters counting towards the total embed limit
+func embedLength(embed *discordgo.MessageEmbed) int {
+	length := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	if embed.Author != nil {
+		length += utf8.RuneCountInString(embed.Author.Name)
+	}
+	if embed.Footer != nil {
+		length += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	for _, field := range embed.Fields {
+		length += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	return length
+}
+
+// truncate shortens the given string to the given amount of characters
+func truncate(str string, length int) string {
+	runes := []rune(str)
+	if len(runes) <= length {
+		return str
+	}
+	return string(runes[:length-1]) + "…"
+}
@@ -0,0 +1,152 @@
+package dgc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// AllowedMentionType represents a type of mention that may be parsed out of a message's content
+type AllowedMentionType string
+
+// The mention types Discord is able to parse out of a message's content
+const (
+	AllowedMentionTypeUsers    AllowedMentionType = "users"
+	AllowedMentionTypeRoles    AllowedMentionType = "roles"
+	AllowedMentionTypeEveryone AllowedMentionType = "everyone"
+)
+
+// AllowedMentions represents the mentions a message is allowed to ping
+type AllowedMentions struct {
+	Parse       []AllowedMentionType `json:"parse"`
+	Users       []string             `json:"users,omitempty"`
+	Roles       []string             `json:"roles,omitempty"`
+	RepliedUser bool                 `json:"replied_user"`
+}
+
+// messageReference represents a reference to the message another message replies to
+type messageReference struct {
+	MessageID string `json:"message_id"`
+	ChannelID string `json:"channel_id,omitempty"`
+	GuildID   string `json:"guild_id,omitempty"`
+}
+
+// messageSend represents the payload of a message sent or edited by the router
+type messageSend struct {
+	Content          string                  `json:"content"`
+	Embed            *discordgo.MessageEmbed `json:"embed,omitempty"`
+	AllowedMentions  *AllowedMentions        `json:"allowed_mentions,omitempty"`
+	MessageReference *messageReference       `json:"message_reference,omitempty"`
+	Files            []*discordgo.File       `json:"-"`
+}
+
+// fileNameEscaper escapes file names for the use inside a multipart header
+var fileNameEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// prepare makes sure the payload is valid before it gets sent to Discord
+func (data *messageSend) prepare() {
+	if data.Embed != nil && data.Embed.Type == "" {
+		data.Embed.Type = "rich"
+	}
+	if data.AllowedMentions != nil && data.AllowedMentions.Parse == nil {
+		allowedMentions := *data.AllowedMentions
+		allowedMentions.Parse = []AllowedMentionType{}
+		data.AllowedMentions = &allowedMentions
+	}
+}
+
+// sendMessage sends the given message to the given channel.
+// It is used instead of discordgo's ChannelMessageSendComplex because it doesn't support message references and allowed mentions.
+func sendMessage(session *discordgo.Session, channelID string, data *messageSend) (*discordgo.Message, error) {
+	data.prepare()
+	endpoint := discordgo.EndpointChannelMessages(channelID)
+
+	// Send a simple JSON request if no files have to be uploaded
+	var response []byte
+	var err error
+	if len(data.Files) == 0 {
+		response, err = session.RequestWithBucketID("POST", endpoint, data, endpoint)
+	} else {
+		response, err = sendMultipartMessage(session, endpoint, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the sent message
+	var message *discordgo.Message
+	err = json.Unmarshal(response, &message)
+	return message, err
+}
+
+// sendMultipartMessage sends the given message including its files as a multipart request to the given endpoint
+func sendMultipartMessage(session *discordgo.Session, endpoint string, data *messageSend) ([]byte, error) {
+	body := &bytes.Buffer{}
+	bodyWriter := multipart.NewWriter(body)
+
+	// Write the JSON payload
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := bodyWriter.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = part.Write(payload); err != nil {
+		return nil, err
+	}
+
+	// Write the files
+	for index, file := range data.Files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file%d"; filename="%s"`, index, fileNameEscaper.Replace(file.Name)))
+		header.Set("Content-Type", contentType)
+		part, err := bodyWriter.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.Copy(part, file.Reader); err != nil {
+			return nil, err
+		}
+	}
+	if err = bodyWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return session.RequestWithLockedBucket("POST", endpoint, bodyWriter.FormDataContentType(), body.Bytes(), session.Ratelimiter.LockBucket(endpoint), 0)
+}
+
+// editMessage edits the content and embed of the given message
+func editMessage(session *discordgo.Session, channelID, messageID string, data *messageSend) (*discordgo.Message, error) {
+	data.prepare()
+
+	// Only send the fields that may be edited
+	edit := &messageSend{
+		Content:         data.Content,
+		Embed:           data.Embed,
+		AllowedMentions: data.AllowedMentions,
+	}
+	response, err := session.RequestWithBucketID("PATCH", discordgo.EndpointChannelMessage(channelID, messageID), edit, discordgo.EndpointChannelMessage(channelID, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the edited message
+	var message *discordgo.Message
+	err = json.Unmarshal(response, &message)
+	return message, err
+}
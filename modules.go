@@ -0,0 +1,219 @@
+package dgc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// moduleStorePrefix defines the prefix of the names of the storage maps owned by modules
+const moduleStorePrefix = "dgc_module:"
+
+var (
+	// ErrModuleAlreadyLoaded is returned if a module with the same name has already been loaded
+	ErrModuleAlreadyLoaded = errors.New("a module with the same name has already been loaded")
+
+	// ErrModuleNotLoaded is returned if a module that hasn't been loaded should be used
+	ErrModuleNotLoaded = errors.New("the module hasn't been loaded")
+)
+
+// Module represents a group of commands and middlewares that gets loaded and unloaded as a unit.
+// The middlewares of a module only wrap the commands of the same module.
+type Module interface {
+	Name() string
+	Commands() []*Command
+	Middlewares() []Middleware
+	Init(router *Router) error
+	Close() error
+}
+
+// loadedModule represents a module that has been loaded into a router
+type loadedModule struct {
+	module          Module
+	commands        []*Command
+	middlewares     []Middleware
+	handlerRemovers []func()
+}
+
+// LoadModule initializes the given module and registers its commands.
+// The commands may not share a name or alias with the commands already registered.
+func (router *Router) LoadModule(module Module) error {
+	// Reserve the name of the module
+	name := module.Name()
+	loaded := &loadedModule{
+		module:      module,
+		commands:    module.Commands(),
+		middlewares: module.Middlewares(),
+	}
+	router.modulesMutex.Lock()
+	if router.modules == nil {
+		router.modules = make(map[string]*loadedModule)
+	}
+	if _, ok := router.modules[name]; ok {
+		router.modulesMutex.Unlock()
+		return ErrModuleAlreadyLoaded
+	}
+	router.modules[name] = loaded
+	router.modulesMutex.Unlock()
+
+	// Initialize the module and register its commands
+	if err := module.Init(router); err != nil {
+		router.discardModule(loaded)
+		return err
+	}
+	if err := router.registerModuleCommands(loaded); err != nil {
+		router.discardModule(loaded)
+		module.Close()
+		return err
+	}
+	return nil
+}
+
+// discardModule removes the given module that failed to load including its event handlers
+func (router *Router) discardModule(loaded *loadedModule) {
+	router.modulesMutex.Lock()
+	delete(router.modules, loaded.module.Name())
+	removers := loaded.handlerRemovers
+	loaded.handlerRemovers = nil
+	router.modulesMutex.Unlock()
+
+	for _, remove := range removers {
+		remove()
+	}
+}
+
+// UnloadModule unregisters the commands and event handlers of the module with the given name and closes it
+func (router *Router) UnloadModule(name string) error {
+	router.modulesMutex.Lock()
+	loaded, ok := router.modules[name]
+	if !ok {
+		router.modulesMutex.Unlock()
+		return ErrModuleNotLoaded
+	}
+	delete(router.modules, name)
+	removers := loaded.handlerRemovers
+	loaded.handlerRemovers = nil
+	router.modulesMutex.Unlock()
+
+	// Unregister the commands of the module
+	router.commandsMutex.Lock()
+	commands := make([]*Command, 0, len(router.Commands))
+	for _, command := range router.Commands {
+		if !containsCmd(loaded.commands, command) {
+			commands = append(commands, command)
+		}
+	}
+	router.Commands = commands
+	router.commandsMutex.Unlock()
+
+	// Remove the event handlers and close the module
+	for _, remove := range removers {
+		remove()
+	}
+	return loaded.module.Close()
+}
+
+// ModuleNames returns the sorted names of all loaded modules
+func (router *Router) ModuleNames() []string {
+	router.modulesMutex.RLock()
+	defer router.modulesMutex.RUnlock()
+
+	names := make([]string, 0, len(router.modules))
+	for name := range router.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ModuleStore returns the storage map owned by the given module
+func (router *Router) ModuleStore(module Module) *ObjectsMap {
+	return router.Store(moduleStorePrefix + module.Name())
+}
+
+// AddModuleHandler registers the given event handler to the given session on behalf of the given module.
+// The handler gets removed once the module is unloaded or the router shuts down.
+func (router *Router) AddModuleHandler(module Module, session *discordgo.Session, handler interface{}) error {
+	router.modulesMutex.Lock()
+	defer router.modulesMutex.Unlock()
+
+	loaded, ok := router.modules[module.Name()]
+	if !ok {
+		return ErrModuleNotLoaded
+	}
+	loaded.handlerRemovers = append(loaded.handlerRemovers, session.AddHandler(handler))
+	return nil
+}
+
+// registerModuleCommands registers the commands of the given module if none of them conflicts with an existing one
+func (router *Router) registerModuleCommands(loaded *loadedModule) error {
+	router.commandsMutex.Lock()
+	defer router.commandsMutex.Unlock()
+
+	for _, command := range loaded.commands {
+		for _, identifier := range append([]string{command.Name}, command.Aliases...) {
+			if indexOfCmd(router.Commands, identifier) >= 0 {
+				return fmt.Errorf("the command '%s' of the module '%s' conflicts with an existing command", identifier, loaded.module.Name())
+			}
+		}
+	}
+	router.Commands = append(append([]*Command{}, router.Commands...), loaded.commands...)
+	return nil
+}
+
+// updateModuleCommands replaces every command of the loaded modules with the result of the given function.
+// Commands for which nil is returned are removed from their module. The commands mutex has to be locked.
+func (router *Router) updateModuleCommands(update func(command *Command) *Command) {
+	router.modulesMutex.Lock()
+	defer router.modulesMutex.Unlock()
+
+	for _, loaded := range router.modules {
+		commands := make([]*Command, 0, len(loaded.commands))
+		for _, command := range loaded.commands {
+			if updated := update(command); updated != nil {
+				commands = append(commands, updated)
+			}
+		}
+		loaded.commands = commands
+	}
+}
+
+// moduleMiddlewares returns the middlewares of the module the given command belongs to
+func (router *Router) moduleMiddlewares(command *Command) []Middleware {
+	router.modulesMutex.RLock()
+	defer router.modulesMutex.RUnlock()
+
+	for _, loaded := range router.modules {
+		if containsCmd(loaded.commands, command) {
+			return loaded.middlewares
+		}
+	}
+	return nil
+}
+
+// removeModuleHandlers removes the event handlers registered on behalf of all loaded modules
+func (router *Router) removeModuleHandlers() {
+	router.modulesMutex.Lock()
+	var removers []func()
+	for _, loaded := range router.modules {
+		removers = append(removers, loaded.handlerRemovers...)
+		loaded.handlerRemovers = nil
+	}
+	router.modulesMutex.Unlock()
+
+	for _, remove := range removers {
+		remove()
+	}
+}
+
+// containsCmd checks whether or not the given commands contain the given command
+func containsCmd(commands []*Command, command *Command) bool {
+	for _, candidate := range commands {
+		if candidate == command {
+			return true
+		}
+	}
+	return false
+}
@@ -1,19 +1,46 @@
 package dgc
 
-import "sync"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// objectsMapCleanupInterval defines how often expired
+// values get removed from an ObjectsMap
+const objectsMapCleanupInterval = time.Minute
+
+var (
+	// ErrObjectNotFound is returned by the typed getters
+	// if the map doesn't contain the requested key.
+	ErrObjectNotFound = errors.New("the object could not be found")
+
+	// ErrObjectType is returned by the typed getters if
+	// the value can't be converted to the requested type.
+	ErrObjectType = errors.New("the object has an unexpected type")
+)
 
 // ObjectsMap wraps a map[string]interface
 // to provide thread safe access endpoints.
 type ObjectsMap struct {
-	mutex    sync.RWMutex
-	innerMap map[string]interface{}
+	mutex       sync.RWMutex
+	innerMap    map[string]interface{}
+	expirations map[string]time.Time
+	cleaning    bool
+	binding     *storageBinding
 }
 
 // newObjectsMap initializes a new
 // ObjectsMap instance
 func newObjectsMap() *ObjectsMap {
 	return &ObjectsMap{
-		innerMap: make(map[string]interface{}),
+		innerMap:    make(map[string]interface{}),
+		expirations: make(map[string]time.Time),
 	}
 }
 
@@ -25,8 +52,7 @@ func (om *ObjectsMap) Get(key string) (interface{}, bool) {
 	om.mutex.RLock()
 	defer om.mutex.RUnlock()
 
-	v, ok := om.innerMap[key]
-	return v, ok
+	return om.get(key, time.Now())
 }
 
 // MustGet wraps Get but only returns the
@@ -39,12 +65,165 @@ func (om *ObjectsMap) MustGet(key string) interface{} {
 	return v
 }
 
+// GetString returns the value of the given
+// key if it is a string.
+func (om *ObjectsMap) GetString(key string) (string, error) {
+	v, ok := om.Get(key)
+	if !ok {
+		return "", notFoundError(key)
+	}
+	str, ok := v.(string)
+	if !ok {
+		return "", typeError(key, v, "string")
+	}
+	return str, nil
+}
+
+// GetInt returns the value of the given key
+// if it is an integer. Other numeric types
+// are converted if they hold an integral
+// value, like the float64 values decoded
+// from JSON.
+func (om *ObjectsMap) GetInt(key string) (int, error) {
+	v, ok := om.Get(key)
+	if !ok {
+		return 0, notFoundError(key)
+	}
+	i, ok := toInt64(v)
+	if !ok || int64(int(i)) != i {
+		return 0, typeError(key, v, "int")
+	}
+	return int(i), nil
+}
+
+// GetDuration returns the value of the given
+// key if it is a duration. Integral numbers
+// are interpreted as nanoseconds and strings
+// are parsed using time.ParseDuration.
+func (om *ObjectsMap) GetDuration(key string) (time.Duration, error) {
+	v, ok := om.Get(key)
+	if !ok {
+		return 0, notFoundError(key)
+	}
+	switch value := v.(type) {
+	case time.Duration:
+		return value, nil
+	case string:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, typeError(key, v, "time.Duration")
+		}
+		return duration, nil
+	}
+	i, ok := toInt64(v)
+	if !ok {
+		return 0, typeError(key, v, "time.Duration")
+	}
+	return time.Duration(i), nil
+}
+
+// GetInto stores the value of the given key
+// in the value dst points to. The value is
+// assigned or converted if possible and
+// decoded from its JSON representation
+// otherwise.
+func (om *ObjectsMap) GetInto(key string, dst interface{}) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return errors.New("the destination has to be a non-nil pointer")
+	}
+	target = target.Elem()
+
+	v, ok := om.Get(key)
+	if !ok {
+		return notFoundError(key)
+	}
+	if v == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	// Assign or convert the value directly
+	value := reflect.ValueOf(v)
+	if value.Type().AssignableTo(target.Type()) {
+		target.Set(value)
+		return nil
+	}
+	if isNumberKind(value.Kind()) && isNumberKind(target.Kind()) {
+		if !isIntegerKind(target.Kind()) {
+			target.Set(value.Convert(target.Type()))
+			return nil
+		}
+		if !setInteger(target, value) {
+			return typeError(key, v, target.Type().String())
+		}
+		return nil
+	}
+
+	// Decode the JSON representation of the value
+	data, err := json.Marshal(v)
+	if err != nil {
+		return typeError(key, v, target.Type().String())
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return typeError(key, v, target.Type().String())
+	}
+	return nil
+}
+
 // Set sets a value to the map by key.
 func (om *ObjectsMap) Set(key string, val interface{}) {
 	om.mutex.Lock()
 	defer om.mutex.Unlock()
 
 	om.innerMap[key] = val
+	delete(om.expirations, key)
+	om.persist(key)
+}
+
+// SetWithTTL sets a value to the map by key
+// which expires after the given duration.
+func (om *ObjectsMap) SetWithTTL(key string, val interface{}, ttl time.Duration) {
+	om.mutex.Lock()
+	defer om.mutex.Unlock()
+
+	om.innerMap[key] = val
+	om.expirations[key] = time.Now().Add(ttl)
+	om.startCleaner()
+	om.persist(key)
+}
+
+// GetOrSet returns the value of the given key
+// and true if it exists. Else, the given value
+// is set and returned together with false.
+func (om *ObjectsMap) GetOrSet(key string, val interface{}) (interface{}, bool) {
+	om.mutex.Lock()
+	defer om.mutex.Unlock()
+
+	if v, ok := om.get(key, time.Now()); ok {
+		return v, true
+	}
+	om.innerMap[key] = val
+	delete(om.expirations, key)
+	om.persist(key)
+	return val, false
+}
+
+// CompareAndSwap sets the value of the given
+// key to new if its current value equals old.
+// It returns whether or not the value was
+// swapped.
+func (om *ObjectsMap) CompareAndSwap(key string, old, new interface{}) bool {
+	om.mutex.Lock()
+	defer om.mutex.Unlock()
+
+	v, ok := om.get(key, time.Now())
+	if !ok || !objectsEqual(v, old) {
+		return false
+	}
+	om.innerMap[key] = new
+	om.persist(key)
+	return true
 }
 
 // Delete removes a key-value pair from the map.
@@ -53,4 +232,323 @@ func (om *ObjectsMap) Delete(key string) {
 	defer om.mutex.Unlock()
 
 	delete(om.innerMap, key)
+	delete(om.expirations, key)
+	if om.binding != nil {
+		om.binding.delete(key)
+	}
+}
+
+// Keys returns the sorted keys of all values
+// in the map.
+func (om *ObjectsMap) Keys() []string {
+	om.mutex.RLock()
+	defer om.mutex.RUnlock()
+
+	now := time.Now()
+	keys := make([]string, 0, len(om.innerMap))
+	for key := range om.innerMap {
+		if _, ok := om.get(key, now); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Range calls f for every key-value pair in
+// the map until it returns false. The map may
+// be modified inside of f.
+func (om *ObjectsMap) Range(f func(key string, val interface{}) bool) {
+	for _, key := range om.Keys() {
+		if v, ok := om.Get(key); ok && !f(key, v) {
+			return
+		}
+	}
+}
+
+// Len returns the amount of values in the map.
+func (om *ObjectsMap) Len() int {
+	return len(om.Keys())
+}
+
+// Clear removes all key-value pairs from the
+// map.
+func (om *ObjectsMap) Clear() {
+	om.mutex.Lock()
+	defer om.mutex.Unlock()
+
+	if om.binding != nil {
+		for key := range om.innerMap {
+			om.binding.delete(key)
+		}
+	}
+	om.innerMap = make(map[string]interface{})
+	om.expirations = make(map[string]time.Time)
+}
+
+// Bind binds the map to the given namespace of
+// the given storage backend. The values stored
+// in the namespace are loaded into the map and
+// every later modification is written through
+// in the background, see Flush.
+func (om *ObjectsMap) Bind(backend StorageBackend, namespace string, options *StorageOptions) error {
+	// Define the default options on a copy, as the given options may be shared by several maps
+	if options == nil {
+		options = &StorageOptions{}
+	}
+	copied := *options
+	options = &copied
+	if options.Codec == nil {
+		options.Codec = DefaultStorageCodec
+	}
+	binding := newStorageBinding(backend, namespace, options)
+
+	// Load the stored values
+	records, err := backend.Load(namespace)
+	if err != nil {
+		return err
+	}
+
+	om.mutex.Lock()
+	defer om.mutex.Unlock()
+
+	now := time.Now()
+	for key, data := range records {
+		encoded, expiration, err := decodeStoredObject(data)
+		if err != nil {
+			return fmt.Errorf("could not load '%s': %w", key, err)
+		}
+		if !expiration.IsZero() && !now.Before(expiration) {
+			binding.delete(key)
+			continue
+		}
+		value, err := options.Codec.Decode(encoded)
+		if err != nil {
+			return fmt.Errorf("could not load '%s': %w", key, err)
+		}
+		om.innerMap[key] = value
+		delete(om.expirations, key)
+		if !expiration.IsZero() {
+			om.expirations[key] = expiration
+			om.startCleaner()
+		}
+	}
+
+	// Persist the values that were set before binding the map
+	om.binding = binding
+	for key := range om.innerMap {
+		if _, ok := records[key]; !ok {
+			om.persist(key)
+		}
+	}
+	return nil
+}
+
+// Flush waits until all modifications have
+// been written to the storage backend the
+// map is bound to.
+func (om *ObjectsMap) Flush() {
+	om.mutex.RLock()
+	binding := om.binding
+	om.mutex.RUnlock()
+
+	if binding != nil {
+		binding.wait()
+	}
+}
+
+// unbind detaches the map from its storage
+// backend and waits until the modifications
+// queued before have been written. Later
+// modifications are kept in memory only.
+func (om *ObjectsMap) unbind() {
+	om.mutex.Lock()
+	binding := om.binding
+	om.binding = nil
+	om.mutex.Unlock()
+
+	if binding != nil {
+		binding.wait()
+	}
+}
+
+// persist queues writing the value of the
+// given key through to the storage backend
+// the map is bound to. The mutex has to be
+// locked.
+func (om *ObjectsMap) persist(key string) {
+	if om.binding != nil {
+		om.binding.save(key, om.innerMap[key], om.expirations[key])
+	}
+}
+
+// get returns the value of the given key if it
+// hasn't expired yet. The mutex has to be locked.
+func (om *ObjectsMap) get(key string, now time.Time) (interface{}, bool) {
+	v, ok := om.innerMap[key]
+	if !ok {
+		return nil, false
+	}
+	if expiration, ok := om.expirations[key]; ok && !now.Before(expiration) {
+		return nil, false
+	}
+	return v, true
+}
+
+// startCleaner starts the goroutine removing
+// expired values if it isn't running yet. The
+// mutex has to be locked.
+func (om *ObjectsMap) startCleaner() {
+	if om.cleaning {
+		return
+	}
+	om.cleaning = true
+	go func() {
+		ticker := time.NewTicker(objectsMapCleanupInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			if !om.cleanup(now) {
+				return
+			}
+		}
+	}()
+}
+
+// cleanup removes all expired values and
+// returns false if no values expire anymore,
+// which stops the cleaner.
+func (om *ObjectsMap) cleanup(now time.Time) bool {
+	om.mutex.Lock()
+	defer om.mutex.Unlock()
+
+	for key, expiration := range om.expirations {
+		if !now.Before(expiration) {
+			delete(om.innerMap, key)
+			delete(om.expirations, key)
+			if om.binding != nil {
+				om.binding.delete(key)
+			}
+		}
+	}
+	if len(om.expirations) == 0 {
+		om.cleaning = false
+		return false
+	}
+	return true
+}
+
+// notFoundError returns the error for a
+// missing key.
+func notFoundError(key string) error {
+	return fmt.Errorf("%w: '%s'", ErrObjectNotFound, key)
+}
+
+// typeError returns the error for a value
+// that can't be converted to the expected type.
+func typeError(key string, v interface{}, expected string) error {
+	return fmt.Errorf("%w: '%s' is %T, not %s", ErrObjectType, key, v, expected)
+}
+
+// toInt64 converts integers and integral
+// floats to int64.
+func toInt64(v interface{}) (int64, bool) {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f := value.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(f), true
+	}
+	if number, ok := v.(json.Number); ok {
+		i, err := number.Int64()
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// setInteger sets the given integer target to the
+// given numeric value if it is integral and fits
+// into the range of the target.
+func setInteger(target, value reflect.Value) bool {
+	// Determine the sign and magnitude of the value
+	var negative bool
+	var magnitude uint64
+	switch {
+	case isIntegerKind(value.Kind()) && value.Kind() >= reflect.Uint:
+		magnitude = value.Uint()
+	case isIntegerKind(value.Kind()):
+		i := value.Int()
+		negative = i < 0
+		if negative {
+			magnitude = uint64(-(i + 1)) + 1
+		} else {
+			magnitude = uint64(i)
+		}
+	default:
+		f := value.Float()
+		if f != math.Trunc(f) || math.Abs(f) >= 1<<64 {
+			return false
+		}
+		negative = f < 0
+		magnitude = uint64(math.Abs(f))
+	}
+
+	// Check the range of the target
+	if target.Kind() >= reflect.Uint {
+		if negative && magnitude != 0 || target.OverflowUint(magnitude) {
+			return false
+		}
+		target.SetUint(magnitude)
+		return true
+	}
+	if negative {
+		if magnitude > 1<<63 || target.OverflowInt(-int64(magnitude-1)-1) {
+			return false
+		}
+		target.SetInt(-int64(magnitude-1) - 1)
+		return true
+	}
+	if magnitude > math.MaxInt64 || target.OverflowInt(int64(magnitude)) {
+		return false
+	}
+	target.SetInt(int64(magnitude))
+	return true
+}
+
+// isNumberKind checks whether or not the
+// given kind is a numeric kind.
+func isNumberKind(kind reflect.Kind) bool {
+	return isIntegerKind(kind) || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// isIntegerKind checks whether or not the
+// given kind is an integer kind.
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// objectsEqual compares both values without
+// panicking on uncomparable types.
+func objectsEqual(v1, v2 interface{}) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == nil && v2 == nil
+	}
+	if reflect.TypeOf(v1) != reflect.TypeOf(v2) || !reflect.TypeOf(v1).Comparable() {
+		return false
+	}
+	return v1 == v2
 }
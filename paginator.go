@@ -0,0 +1,287 @@
+package dgc
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// defaultPaginatorTimeout defines how long a paginator may be controlled after its last interaction if the options don't define a timeout
+const defaultPaginatorTimeout = 5 * time.Minute
+
+// ErrNoPages is returned if a paginator should be started without any pages
+var ErrNoPages = errors.New("at least one page is required")
+
+// PaginatorEmojis represents the emojis used to control a paginator.
+// Custom emojis are defined as 'name:id' or in their message format, controls with an empty emoji are left out.
+type PaginatorEmojis struct {
+	First    string
+	Previous string
+	Stop     string
+	Next     string
+	Last     string
+}
+
+// DefaultPaginatorEmojis defines the emojis used if a paginator doesn't define its own ones
+var DefaultPaginatorEmojis = &PaginatorEmojis{
+	First:    "⏮️",
+	Previous: "⬅️",
+	Stop:     "❌",
+	Next:     "➡️",
+	Last:     "⏭️",
+}
+
+// PaginatorOptions represents the options of a paginator
+type PaginatorOptions struct {
+	Emojis       *PaginatorEmojis
+	AuthorOnly   bool
+	Timeout      time.Duration
+	PageFooters  bool
+	DeleteOnStop bool
+}
+
+// Paginator represents a message whose pages may be switched using reactions
+type Paginator struct {
+	ChannelID    string
+	MessageID    string
+	mutex        sync.Mutex
+	router       *Router
+	session      *discordgo.Session
+	ownerID      string
+	pages        []*discordgo.MessageSend
+	page         int
+	options      *PaginatorOptions
+	timer        *time.Timer
+	subscription *eventSubscription
+	stopped      bool
+}
+
+// Paginate responds with the first one of the given pages and lets the user switch between them using reactions
+func (ctx *Ctx) Paginate(pages []*discordgo.MessageEmbed, options *PaginatorOptions) (*Paginator, error) {
+	messages := make([]*discordgo.MessageSend, len(pages))
+	for index, page := range pages {
+		messages[index] = &discordgo.MessageSend{
+			Embed: page,
+		}
+	}
+	return ctx.paginate(messages, options)
+}
+
+// paginate responds with the first one of the given pages and lets the user switch between them using reactions
+func (ctx *Ctx) paginate(pages []*discordgo.MessageSend, options *PaginatorOptions) (*Paginator, error) {
+	if len(pages) == 0 {
+		return nil, ErrNoPages
+	}
+
+	// Define the default options on a copy, as the given options may be shared by concurrently running commands
+	if options == nil {
+		options = &PaginatorOptions{}
+	}
+	copied := *options
+	options = &copied
+	emojis := options.Emojis
+	if emojis == nil {
+		emojis = DefaultPaginatorEmojis
+	}
+	options.Emojis = &PaginatorEmojis{
+		First:    normalizeEmoji(emojis.First),
+		Previous: normalizeEmoji(emojis.Previous),
+		Stop:     normalizeEmoji(emojis.Stop),
+		Next:     normalizeEmoji(emojis.Next),
+		Last:     normalizeEmoji(emojis.Last),
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultPaginatorTimeout
+	}
+
+	// Send the first page
+	paginator := &Paginator{
+		ChannelID: ctx.Event.ChannelID,
+		router:    ctx.Router,
+		session:   ctx.Session,
+		ownerID:   ctx.Event.Author.ID,
+		pages:     pages,
+		options:   options,
+	}
+	first := paginator.render()
+	message, err := ctx.Respond(&Response{
+		Content: first.Content,
+		Embed:   first.Embed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	paginator.ChannelID = message.ChannelID
+	paginator.MessageID = message.ID
+
+	// Listen for reactions and stop the paginator after the timeout
+	paginator.mutex.Lock()
+	paginator.subscription = ctx.Router.eventBus().SubscribeReactions(ctx.Session, message.ID, paginator.handleReaction)
+	paginator.timer = time.AfterFunc(options.Timeout, paginator.Stop)
+	paginator.mutex.Unlock()
+
+	// Add the control reactions
+	for _, emoji := range paginator.controls() {
+		ctx.Session.MessageReactionAdd(paginator.ChannelID, paginator.MessageID, emoji)
+	}
+	return paginator, nil
+}
+
+// Page returns the index of the current page
+func (paginator *Paginator) Page() int {
+	paginator.mutex.Lock()
+	defer paginator.mutex.Unlock()
+
+	return paginator.page
+}
+
+// SetPage switches to the page with the given index
+func (paginator *Paginator) SetPage(page int) error {
+	paginator.mutex.Lock()
+	defer paginator.mutex.Unlock()
+
+	return paginator.setPage(page)
+}
+
+// Stop stops the paginator and removes its control reactions
+func (paginator *Paginator) Stop() {
+	if paginator.stop() {
+		paginator.session.MessageReactionsRemoveAll(paginator.ChannelID, paginator.MessageID)
+	}
+}
+
+// stop stops listening for reactions and returns false if the paginator has already been stopped
+func (paginator *Paginator) stop() bool {
+	paginator.mutex.Lock()
+	defer paginator.mutex.Unlock()
+
+	if paginator.stopped {
+		return false
+	}
+	paginator.stopped = true
+	paginator.timer.Stop()
+	paginator.router.eventBus().Unsubscribe(paginator.subscription)
+	return true
+}
+
+// setPage switches to the page with the given index, the mutex has to be locked
+func (paginator *Paginator) setPage(page int) error {
+	// Keep the page inside the bounds
+	if page < 0 {
+		page = 0
+	}
+	if page >= len(paginator.pages) {
+		page = len(paginator.pages) - 1
+	}
+	if page == paginator.page {
+		return nil
+	}
+
+	// Edit the message
+	paginator.page = page
+	rendered := paginator.render()
+	_, err := editMessage(paginator.session, paginator.ChannelID, paginator.MessageID, &messageSend{
+		Content:         rendered.Content,
+		Embed:           rendered.Embed,
+		AllowedMentions: paginator.router.AllowedMentions,
+	})
+	return err
+}
+
+// render returns the current page including its page number footer if needed
+func (paginator *Paginator) render() *discordgo.MessageSend {
+	page := paginator.pages[paginator.page]
+	if !paginator.options.PageFooters || page.Embed == nil {
+		return page
+	}
+
+	// Copy the embed to not modify the given one
+	embed := *page.Embed
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: "Page " + strconv.Itoa(paginator.page+1) + "/" + strconv.Itoa(len(paginator.pages)),
+	}
+	return &discordgo.MessageSend{
+		Content: page.Content,
+		Embed:   &embed,
+	}
+}
+
+// controls returns the emojis of all the controls used by the paginator
+func (paginator *Paginator) controls() []string {
+	emojis := paginator.options.Emojis
+	candidates := []string{emojis.Stop}
+	if len(paginator.pages) > 1 {
+		candidates = []string{emojis.First, emojis.Previous, emojis.Stop, emojis.Next, emojis.Last}
+	}
+
+	var controls []string
+	for _, emoji := range candidates {
+		if emoji != "" {
+			controls = append(controls, emoji)
+		}
+	}
+	return controls
+}
+
+// handleReaction handles a reaction added to the paginated message
+func (paginator *Paginator) handleReaction(session *discordgo.Session, event *discordgo.MessageReactionAdd) {
+	// Define useful variables
+	emojis := paginator.options.Emojis
+	reactionName := event.Emoji.APIName()
+
+	// Check whether or not the user is allowed to control the paginator
+	if paginator.options.AuthorOnly && event.UserID != paginator.ownerID {
+		session.MessageReactionRemove(paginator.ChannelID, paginator.MessageID, reactionName, event.UserID)
+		return
+	}
+
+	// Stop the paginator
+	if reactionName == emojis.Stop {
+		if paginator.options.DeleteOnStop {
+			if paginator.stop() {
+				session.ChannelMessageDelete(paginator.ChannelID, paginator.MessageID)
+			}
+			return
+		}
+		paginator.Stop()
+		return
+	}
+
+	// Switch the page
+	paginator.mutex.Lock()
+	if paginator.stopped {
+		paginator.mutex.Unlock()
+		return
+	}
+	switch reactionName {
+	case emojis.First:
+		paginator.setPage(0)
+	case emojis.Previous:
+		paginator.setPage(paginator.page - 1)
+	case emojis.Next:
+		paginator.setPage(paginator.page + 1)
+	case emojis.Last:
+		paginator.setPage(len(paginator.pages) - 1)
+	default:
+		paginator.mutex.Unlock()
+		return
+	}
+	paginator.timer.Reset(paginator.options.Timeout)
+	paginator.mutex.Unlock()
+
+	// Remove the reaction so it can be used again
+	session.MessageReactionRemove(paginator.ChannelID, paginator.MessageID, reactionName, event.UserID)
+}
+
+// normalizeEmoji converts the given emoji into the 'name:id' form used by the reaction endpoints and events.
+// Custom emojis may be given in their message format like '<:name:id>' or '<a:name:id>'.
+func normalizeEmoji(emoji string) string {
+	if strings.HasPrefix(emoji, "<") && strings.HasSuffix(emoji, ">") {
+		emoji = strings.TrimPrefix(strings.TrimPrefix(emoji[1:len(emoji)-1], "a"), ":")
+	}
+	return emoji
+}
@@ -0,0 +1,164 @@
+package dgc
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// defaultPromptCancelKeyword defines the keyword used to cancel a prompt if the options don't define one
+	defaultPromptCancelKeyword = "cancel"
+
+	// confirmEmoji defines the emoji used to confirm a confirmation
+	confirmEmoji = "✅"
+
+	// denyEmoji defines the emoji used to deny a confirmation
+	denyEmoji = "❌"
+)
+
+var (
+	// ErrPromptTimeout is returned if nobody answered a prompt in time
+	ErrPromptTimeout = errors.New("the prompt timed out")
+
+	// ErrPromptCancelled is returned if the user answered a prompt with the cancel keyword
+	ErrPromptCancelled = errors.New("the prompt got cancelled")
+
+	// ErrConfirmationTimeout is returned if nobody answered a confirmation in time
+	ErrConfirmationTimeout = errors.New("the confirmation timed out")
+)
+
+// PromptOptions represents the options of a prompt
+type PromptOptions struct {
+	Timeout       time.Duration
+	Filter        func(*discordgo.Message) bool
+	CancelKeyword string
+}
+
+// Prompt sends the given question and waits for the author to answer it in the current channel.
+// The answer is parsed into arguments; its message doesn't get handled as a command.
+func (ctx *Ctx) Prompt(question string, timeout time.Duration) (*Arguments, error) {
+	return ctx.PromptWithOptions(question, &PromptOptions{
+		Timeout: timeout,
+	})
+}
+
+// PromptWithOptions sends the given question and waits for a message matching the filter of the given options.
+// If no filter is defined, the author has to answer in the current channel. An empty question doesn't get sent.
+func (ctx *Ctx) PromptWithOptions(question string, options *PromptOptions) (*Arguments, error) {
+	// Define the default options
+	if options == nil {
+		options = &PromptOptions{}
+	}
+	filter := options.Filter
+	if filter == nil {
+		filter = func(message *discordgo.Message) bool {
+			return message.Author != nil && message.Author.ID == ctx.Event.Author.ID && message.ChannelID == ctx.Event.ChannelID
+		}
+	}
+	cancelKeyword := options.CancelKeyword
+	if cancelKeyword == "" {
+		cancelKeyword = defaultPromptCancelKeyword
+	}
+
+	// Listen for the answer before asking the question to not miss it
+	answers := make(chan *discordgo.Message, 1)
+	bus := ctx.Router.eventBus()
+	subscription := bus.SubscribeMessages(func(message *discordgo.Message) bool {
+		if !filter(message) {
+			return false
+		}
+		select {
+		case answers <- message:
+			return true
+		default:
+			return false
+		}
+	})
+	defer bus.Unsubscribe(subscription)
+
+	// Ask the question
+	if question != "" {
+		if _, err := ctx.Respond(&Response{
+			Content: question,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Wait for the answer
+	var expired <-chan time.Time
+	if options.Timeout > 0 {
+		timer := time.NewTimer(options.Timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case message := <-answers:
+		if equals(message.Content, cancelKeyword, true) {
+			return nil, ErrPromptCancelled
+		}
+		return ParseArguments(message.Content), nil
+	case <-expired:
+		return nil, ErrPromptTimeout
+	case <-ctx.Context().Done():
+		return nil, ctx.Context().Err()
+	}
+}
+
+// Confirm sends the given text and lets the author confirm or deny it using reactions.
+// It returns whether or not the author confirmed it.
+func (ctx *Ctx) Confirm(text string, timeout time.Duration) (bool, error) {
+	// Send the message
+	message, err := ctx.Respond(&Response{
+		Content: text,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// Listen for the reaction of the author
+	answers := make(chan bool, 1)
+	bus := ctx.Router.eventBus()
+	subscription := bus.SubscribeReactions(ctx.Session, message.ID, func(session *discordgo.Session, event *discordgo.MessageReactionAdd) {
+		if event.UserID != ctx.Event.Author.ID {
+			return
+		}
+		var answer bool
+		switch event.Emoji.Name {
+		case confirmEmoji:
+			answer = true
+		case denyEmoji:
+			answer = false
+		default:
+			return
+		}
+		select {
+		case answers <- answer:
+		default:
+		}
+	})
+	defer ctx.Session.MessageReactionsRemoveAll(message.ChannelID, message.ID)
+	defer bus.Unsubscribe(subscription)
+
+	// Add the reactions
+	ctx.Session.MessageReactionAdd(message.ChannelID, message.ID, confirmEmoji)
+	ctx.Session.MessageReactionAdd(message.ChannelID, message.ID, denyEmoji)
+
+	// Wait for the answer
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case answer := <-answers:
+		return answer, nil
+	case <-expired:
+		return false, ErrConfirmationTimeout
+	case <-ctx.Context().Done():
+		return false, ctx.Context().Err()
+	}
+}
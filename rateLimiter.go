@@ -1,6 +1,7 @@
 package dgc
 
 import (
+	"sync"
 	"time"
 
 	"github.com/zekroTJA/timedmap"
@@ -11,11 +12,13 @@ type RateLimiter interface {
 	NotifyExecution(*Ctx) bool
 }
 
-// DefaultRateLimiter represents an internal rate limiter
+// DefaultRateLimiter represents an internal rate limiter keeping the cooldowns in memory.
+// Use a StoreRateLimiter to keep them across restarts.
 type DefaultRateLimiter struct {
 	Cooldown           time.Duration
 	RateLimitedHandler ExecutionHandler
 	executions         *timedmap.TimedMap
+	closeOnce          sync.Once
 }
 
 // NewRateLimiter creates a new rate limiter
@@ -27,6 +30,12 @@ func NewRateLimiter(cooldown, cleanupInterval time.Duration, onRateLimited Execu
 	}
 }
 
+// Close stops cleaning up the expired executions
+func (rateLimiter *DefaultRateLimiter) Close() error {
+	rateLimiter.closeOnce.Do(rateLimiter.executions.StopCleaner)
+	return nil
+}
+
 // NotifyExecution notifies the rate limiter about a new execution and returns whether or not the execution is allowed
 func (rateLimiter *DefaultRateLimiter) NotifyExecution(ctx *Ctx) bool {
 	if rateLimiter.executions.Contains(ctx.Event.Author.ID) {
@@ -42,3 +51,49 @@ func (rateLimiter *DefaultRateLimiter) NotifyExecution(ctx *Ctx) bool {
 	rateLimiter.executions.Set(ctx.Event.Author.ID, time.Now().UnixNano()/1e6, rateLimiter.Cooldown)
 	return true
 }
+
+// StoreRateLimiter represents a rate limiter which keeps the cooldowns in a storage map.
+// If the map is bound to a storage backend, the cooldowns survive restarts.
+// Every rate limiter has to use its own storage map.
+type StoreRateLimiter struct {
+	Cooldown           time.Duration
+	RateLimitedHandler ExecutionHandler
+	mutex              sync.Mutex
+	store              *ObjectsMap
+}
+
+// NewStoreRateLimiter creates a new rate limiter keeping the cooldowns in the given storage map
+func NewStoreRateLimiter(cooldown time.Duration, store *ObjectsMap, onRateLimited ExecutionHandler) RateLimiter {
+	return &StoreRateLimiter{
+		Cooldown:           cooldown,
+		RateLimitedHandler: onRateLimited,
+		store:              store,
+	}
+}
+
+// Close waits until the cooldowns have been written to the storage backend of the storage map
+func (rateLimiter *StoreRateLimiter) Close() error {
+	rateLimiter.store.Flush()
+	return nil
+}
+
+// NotifyExecution notifies the rate limiter about a new execution and returns whether or not the execution is allowed
+func (rateLimiter *StoreRateLimiter) NotifyExecution(ctx *Ctx) bool {
+	rateLimiter.mutex.Lock()
+	nextExecution, err := rateLimiter.store.GetInt(ctx.Event.Author.ID)
+	limited := err == nil
+	if !limited {
+		nextExecution = int(time.Now().Add(rateLimiter.Cooldown).UnixNano() / 1e6)
+		rateLimiter.store.SetWithTTL(ctx.Event.Author.ID, nextExecution, rateLimiter.Cooldown)
+	}
+	rateLimiter.mutex.Unlock()
+
+	if limited {
+		if rateLimiter.RateLimitedHandler != nil {
+			ctx.CustomObjects.Set("dgc_nextExecution", time.Unix(0, int64(nextExecution)*1e6))
+			rateLimiter.RateLimitedHandler(ctx)
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package dgc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekroTJA/timedmap"
+)
+
+// defaultResponseLifetime defines how long the responses to an invoking message are remembered if the router doesn't define a lifetime
+const defaultResponseLifetime = 5 * time.Minute
+
+// trackedResponse represents a message the router sent in response to an invoking message
+type trackedResponse struct {
+	ChannelID string
+	MessageID string
+}
+
+// editKey represents the key of the last processed edit of an invoking message
+type editKey struct {
+	messageID string
+}
+
+// responseTracker keeps track of the messages the router sent in response to invoking messages
+type responseTracker struct {
+	mutex     sync.Mutex
+	lifetime  time.Duration
+	responses *timedmap.TimedMap
+}
+
+// newResponseTracker creates a new response tracker remembering responses for the given lifetime
+func newResponseTracker(lifetime time.Duration) *responseTracker {
+	return &responseTracker{
+		lifetime:  lifetime,
+		responses: timedmap.New(lifetime / 5),
+	}
+}
+
+// Get returns all the tracked responses to the given invoking message
+func (tracker *responseTracker) Get(invokingMessageID string) []*trackedResponse {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	responses, ok := tracker.responses.GetValue(invokingMessageID).([]*trackedResponse)
+	if !ok {
+		return nil
+	}
+	return append([]*trackedResponse{}, responses...)
+}
+
+// Track adds the given response message to the responses of the given invoking message
+func (tracker *responseTracker) Track(invokingMessageID string, response *discordgo.Message) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	responses, _ := tracker.responses.GetValue(invokingMessageID).([]*trackedResponse)
+	responses = append(responses, &trackedResponse{
+		ChannelID: response.ChannelID,
+		MessageID: response.ID,
+	})
+	tracker.responses.Set(invokingMessageID, responses, tracker.lifetime)
+}
+
+// TrackEdit remembers the given edit timestamp of the given invoking message and returns false if it has been processed already
+func (tracker *responseTracker) TrackEdit(invokingMessageID, editedTimestamp string) bool {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	key := editKey{messageID: invokingMessageID}
+	if tracker.responses.GetValue(key) == editedTimestamp {
+		return false
+	}
+	tracker.responses.Set(key, editedTimestamp, tracker.lifetime)
+	return true
+}
+
+// Remove removes and returns all the tracked responses to the given invoking message
+func (tracker *responseTracker) Remove(invokingMessageID string) []*trackedResponse {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	responses, _ := tracker.responses.GetValue(invokingMessageID).([]*trackedResponse)
+	tracker.responses.Remove(invokingMessageID)
+	return responses
+}
+
+// tracksResponses checks whether or not the router has to track its responses as it deletes or edits them later on
+func (router *Router) tracksResponses() bool {
+	return router.DeleteResponses || router.ExecuteOnEdit
+}
+
+// responseLifetime returns how long the responses to an invoking message are remembered
+func (router *Router) responseLifetime() time.Duration {
+	if router.ResponseLifetime <= 0 {
+		return defaultResponseLifetime
+	}
+	return router.ResponseLifetime
+}
+
+// responseTracker returns the response tracker of the router and creates it if needed
+func (router *Router) responseTracker() *responseTracker {
+	router.responsesOnce.Do(func() {
+		router.responses = newResponseTracker(router.responseLifetime())
+	})
+	return router.responses
+}
+
+// DeleteHandler provides the discordgo handler deleting the responses to a deleted invoking message.
+// Responses are only tracked if the router deletes responses or executes commands on edits.
+func (router *Router) DeleteHandler() func(*discordgo.Session, *discordgo.MessageDelete) {
+	return func(session *discordgo.Session, event *discordgo.MessageDelete) {
+		if !router.tracksResponses() {
+			return
+		}
+		for _, response := range router.responseTracker().Remove(event.ID) {
+			session.ChannelMessageDelete(response.ChannelID, response.MessageID)
+		}
+	}
+}
+
+// isWithinResponseLifetime checks whether or not the given message is young enough for its responses to be tracked
+func (router *Router) isWithinResponseLifetime(message *discordgo.Message) bool {
+	timestamp, err := message.Timestamp.Parse()
+	if err != nil {
+		return false
+	}
+	return time.Since(timestamp) < router.responseLifetime()
+}
+
+// send sends the given message to the given channel and tracks it as a response to the invoking message if the router needs to.
+// If the command got re-executed because the invoking message was edited, the previous responses get edited instead.
+// As files can't be attached by editing a message, previous responses get replaced by responses containing files.
+func (ctx *Ctx) send(channelID string, data *messageSend) (*discordgo.Message, error) {
+	if !ctx.Router.tracksResponses() {
+		return sendMessage(ctx.Session, channelID, data)
+	}
+	tracker := ctx.Router.responseTracker()
+
+	// Reuse the corresponding previous response if the command got re-executed
+	if previous := ctx.reexecution.next(); previous != nil {
+		if len(data.Files) == 0 {
+			message, err := editMessage(ctx.Session, previous.ChannelID, previous.MessageID, data)
+			if err != nil {
+				return nil, err
+			}
+			tracker.Track(ctx.Event.ID, message)
+			return message, nil
+		}
+		ctx.Session.ChannelMessageDelete(previous.ChannelID, previous.MessageID)
+	}
+
+	// Send and track a new response
+	message, err := sendMessage(ctx.Session, channelID, data)
+	if err != nil {
+		return nil, err
+	}
+	tracker.Track(ctx.Event.ID, message)
+	return message, nil
+}
+
+// reexecutionState holds the state of a command execution caused by an edited invoking message.
+// Every execution belonging to it holds a reference; once all of them are released, the previous responses that weren't reused are passed to finish.
+type reexecutionState struct {
+	mutex      sync.Mutex
+	previous   []*trackedResponse
+	responses  int
+	references int
+	finished   bool
+	finish     func(unused []*trackedResponse)
+}
+
+// newReexecutionState creates a new re-execution state reusing the given previous responses
+func newReexecutionState(previous []*trackedResponse, finish func(unused []*trackedResponse)) *reexecutionState {
+	return &reexecutionState{
+		previous: previous,
+		finish:   finish,
+	}
+}
+
+// next returns the previous response the next response should replace or nil if there is none left
+func (state *reexecutionState) next() *trackedResponse {
+	if state == nil {
+		return nil
+	}
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if state.finished || state.responses >= len(state.previous) {
+		return nil
+	}
+	state.responses++
+	return state.previous[state.responses-1]
+}
+
+// acquire adds a reference to the state
+func (state *reexecutionState) acquire() {
+	if state == nil {
+		return
+	}
+	state.mutex.Lock()
+	state.references++
+	state.mutex.Unlock()
+}
+
+// release removes a reference from the state and finishes it once no references are left
+func (state *reexecutionState) release() {
+	if state == nil {
+		return
+	}
+	state.mutex.Lock()
+	state.references--
+	if state.references > 0 || state.finished {
+		state.mutex.Unlock()
+		return
+	}
+	state.finished = true
+	var unused []*trackedResponse
+	if state.responses < len(state.previous) {
+		unused = state.previous[state.responses:]
+	}
+	state.mutex.Unlock()
+
+	if state.finish != nil {
+		state.finish(unused)
+	}
+}
@@ -1,8 +1,11 @@
 package dgc
 
 import (
+	"context"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -12,13 +15,50 @@ var regexSplitting = regexp.MustCompile("\\s+")
 
 // Router represents a DiscordGo command router
 type Router struct {
-	Prefixes         []string
-	IgnorePrefixCase bool
-	BotsAllowed      bool
-	Commands         []*Command
-	Middlewares      []Middleware
-	PingHandler      ExecutionHandler
-	Storage          map[string]*ObjectsMap
+	Prefixes           []string
+	IgnorePrefixCase   bool
+	BotsAllowed        bool
+	Commands           []*Command
+	commandsMutex      sync.RWMutex
+	Middlewares        []Middleware
+	PingHandler        ExecutionHandler
+	CommandSettings    CommandSettingsProvider
+	DisabledHandler    ExecutionHandler
+	UnavailableHandler ExecutionHandler
+	TimeoutHandler     ExecutionHandler
+	BusyHandler        ExecutionHandler
+	InFlightHandler    ExecutionHandler
+	Workers            int
+	QueueSize          int
+	OverflowPolicy     OverflowPolicy
+	ExecuteOnEdit      bool
+	DeleteResponses    bool
+	ResponseLifetime   time.Duration
+	AllowedMentions    *AllowedMentions
+	Storage            map[string]*ObjectsMap
+	StorageBackend     StorageBackend
+	StorageOptions     *StorageOptions
+	storageMutex       sync.RWMutex
+	responses          *responseTracker
+	responsesOnce      sync.Once
+	events             *eventBus
+	eventsOnce         sync.Once
+	rootCtx            context.Context
+	rootCancel         context.CancelFunc
+	rootCtxOnce        sync.Once
+	queue              chan func()
+	workersOnce        sync.Once
+	slots              map[*Command]*executionGate
+	slotsMutex         sync.Mutex
+	inFlight           map[inFlightKey]*executionGate
+	inFlightMutex      sync.Mutex
+	handlerRemovers    []func()
+	lifecycleMutex     sync.RWMutex
+	running            sync.WaitGroup
+	shutDown           bool
+	shutdownOnce       sync.Once
+	modules            map[string]*loadedModule
+	modulesMutex       sync.RWMutex
 }
 
 // Create makes sure all maps get initialized
@@ -29,13 +69,91 @@ func Create(router *Router) *Router {
 
 // RegisterCmd registers a new command
 func (router *Router) RegisterCmd(command *Command) {
-	router.Commands = append(router.Commands, command)
+	router.commandsMutex.Lock()
+	defer router.commandsMutex.Unlock()
+
+	router.Commands = append(append([]*Command{}, router.Commands...), command)
+}
+
+// UnregisterCmd removes the command with the given name or alias and returns whether or not it existed.
+// If the command belongs to a module, it gets removed from the module too.
+func (router *Router) UnregisterCmd(name string) bool {
+	router.commandsMutex.Lock()
+	defer router.commandsMutex.Unlock()
+
+	index := indexOfCmd(router.Commands, name)
+	if index < 0 {
+		return false
+	}
+	removed := router.Commands[index]
+	router.updateModuleCommands(func(command *Command) *Command {
+		if command == removed {
+			return nil
+		}
+		return command
+	})
+	commands := make([]*Command, 0, len(router.Commands)-1)
+	commands = append(commands, router.Commands[:index]...)
+	router.Commands = append(commands, router.Commands[index+1:]...)
+	return true
+}
+
+// ReplaceCmd replaces the command with the given name or alias with the given command and returns whether or not it existed.
+// If the replaced command belongs to a module, the new command belongs to that module instead.
+func (router *Router) ReplaceCmd(name string, command *Command) bool {
+	router.commandsMutex.Lock()
+	defer router.commandsMutex.Unlock()
+
+	index := indexOfCmd(router.Commands, name)
+	if index < 0 {
+		return false
+	}
+	replaced := router.Commands[index]
+	router.updateModuleCommands(func(moduleCommand *Command) *Command {
+		if moduleCommand == replaced {
+			return command
+		}
+		return moduleCommand
+	})
+	commands := append([]*Command{}, router.Commands...)
+	commands[index] = command
+	router.Commands = commands
+	return true
+}
+
+// SetCommands atomically replaces all registered commands with the given ones.
+// Module commands that aren't part of the given commands get removed from their modules.
+func (router *Router) SetCommands(commands []*Command) {
+	router.commandsMutex.Lock()
+	defer router.commandsMutex.Unlock()
+
+	router.updateModuleCommands(func(command *Command) *Command {
+		if containsCmd(router.Commands, command) && !containsCmd(commands, command) {
+			return nil
+		}
+		return command
+	})
+	router.Commands = append([]*Command{}, commands...)
+}
+
+// GetCommands returns a copy of all registered commands
+func (router *Router) GetCommands() []*Command {
+	return append([]*Command{}, router.commands()...)
+}
+
+// commands returns the registered commands.
+// As the slice gets replaced on every modification, it may be iterated without holding the lock.
+func (router *Router) commands() []*Command {
+	router.commandsMutex.RLock()
+	defer router.commandsMutex.RUnlock()
+
+	return router.Commands
 }
 
 // GetCmd returns the command with the given name if it exists
 func (router *Router) GetCmd(name string) *Command {
 	// Loop through all commands to find the correct one
-	for _, command := range router.Commands {
+	for _, command := range router.commands() {
 		// Define the slice to check
 		toCheck := make([]string, len(command.Aliases)+1)
 		toCheck = append(toCheck, command.Name)
@@ -54,78 +172,156 @@ func (router *Router) RegisterMiddleware(middleware Middleware) {
 	router.Middlewares = append(router.Middlewares, middleware)
 }
 
-// InitializeStorage initializes a storage map
-func (router *Router) InitializeStorage(name string) {
-	router.Storage[name] = newObjectsMap()
+// InitializeStorage initializes a storage map and replaces an existing one with the same name.
+// If the router has a storage backend, the map gets bound to the namespace with the given name.
+func (router *Router) InitializeStorage(name string) error {
+	router.storageMutex.Lock()
+	defer router.storageMutex.Unlock()
+
+	_, err := router.createStore(name)
+	return err
 }
 
-// Initialize initializes the message event listener
+// Initialize initializes the message event listeners
 func (router *Router) Initialize(session *discordgo.Session) {
-	session.AddHandler(router.Handler())
+	router.addHandler(session, router.Handler())
+	if router.ExecuteOnEdit {
+		router.addHandler(session, router.UpdateHandler())
+	}
+	if router.DeleteResponses {
+		router.addHandler(session, router.DeleteHandler())
+	}
 }
 
 // Handler provides the discordgo handler for the given router
 func (router *Router) Handler() func(*discordgo.Session, *discordgo.MessageCreate) {
 	return func(session *discordgo.Session, event *discordgo.MessageCreate) {
-		// Define useful variables
-		message := event.Message
-		content := message.Content
+		router.handleMessage(session, event, nil)
+	}
+}
 
-		// Check if the message was sent by a bot
-		if message.Author.Bot && !router.BotsAllowed {
+// UpdateHandler provides the discordgo handler re-executing the commands of edited messages.
+// The responses of the previous execution get edited instead of sending new ones and the ones that didn't get reused are deleted.
+func (router *Router) UpdateHandler() func(*discordgo.Session, *discordgo.MessageUpdate) {
+	return func(session *discordgo.Session, event *discordgo.MessageUpdate) {
+		// Ignore updates that weren't caused by the author editing the content (like embeds being resolved)
+		if event.Author == nil || event.EditedTimestamp == "" {
 			return
 		}
 
-		// Execute the ping handler if the message equals the current bot's mention
-		if (content == "<@!"+session.State.User.ID+">" || content == "<@"+session.State.User.ID+">") && router.PingHandler != nil {
-			router.PingHandler(&Ctx{
-				Session:   session,
-				Event:     event,
-				Arguments: ParseArguments(""),
-				Router:    router,
-			})
+		// Ignore edits of messages whose responses aren't tracked anymore
+		if !router.isWithinResponseLifetime(event.Message) {
 			return
 		}
 
-		// Check if the message starts with one of the defined prefixes
-		hasPrefix, content := stringHasPrefix(content, router.Prefixes, router.IgnorePrefixCase)
-		if !hasPrefix {
+		// Ignore updates that keep the timestamp of an already processed edit, like the message getting pinned
+		if router.tracksResponses() && !router.responseTracker().TrackEdit(event.ID, string(event.EditedTimestamp)) {
 			return
 		}
 
-		// Get rid of additional spaces
-		content = strings.Trim(content, " ")
+		// Re-execute the command and delete the previous responses that didn't get reused afterwards
+		var previous []*trackedResponse
+		if router.tracksResponses() {
+			previous = router.responseTracker().Remove(event.ID)
+		}
+		reexecution := newReexecutionState(previous, func(unused []*trackedResponse) {
+			for _, response := range unused {
+				session.ChannelMessageDelete(response.ChannelID, response.MessageID)
+			}
+		})
+		reexecution.acquire()
+		defer reexecution.release()
+		router.handleMessage(session, &discordgo.MessageCreate{Message: event.Message}, reexecution)
+	}
+}
 
-		// Check if the message is empty after the prefix processing
-		if content == "" {
-			return
+// handleMessage handles the given message event and executes the corresponding command
+func (router *Router) handleMessage(session *discordgo.Session, event *discordgo.MessageCreate, reexecution *reexecutionState) {
+	// Define useful variables
+	message := event.Message
+	content := message.Content
+
+	// Publish the message and check if it got consumed, for example by a prompt
+	if reexecution == nil && router.eventBus().Publish(session, event) {
+		return
+	}
+
+	// Check if the message was sent by a bot
+	if message.Author.Bot && !router.BotsAllowed {
+		return
+	}
+
+	// Execute the ping handler if the message equals the current bot's mention
+	if (content == "<@!"+session.State.User.ID+">" || content == "<@"+session.State.User.ID+">") && router.PingHandler != nil {
+		router.PingHandler(&Ctx{
+			Session:     session,
+			Event:       event,
+			Arguments:   ParseArguments(""),
+			Router:      router,
+			reexecution: reexecution,
+		})
+		return
+	}
+
+	// Check if the message starts with one of the defined prefixes
+	hasPrefix, content := stringHasPrefix(content, router.Prefixes, router.IgnorePrefixCase)
+	if !hasPrefix {
+		return
+	}
+
+	// Get rid of additional spaces
+	content = strings.Trim(content, " ")
+
+	// Check if the message is empty after the prefix processing
+	if content == "" {
+		return
+	}
+
+	// Split the messages at any whitespace
+	parts := regexSplitting.Split(content, -1)
+
+	// Check if the message starts with a command name
+	for _, command := range router.commands() {
+		// Check if the first part is the current command
+		if !stringArrayContains(getIdentifiers(command), parts[0], command.IgnoreCase) {
+			continue
+		}
+		content = strings.Join(parts[1:], " ")
+
+		// Define the command context
+		ctx := &Ctx{
+			Session:       session,
+			Event:         event,
+			Arguments:     ParseArguments(content),
+			CustomObjects: newObjectsMap(),
+			Router:        router,
+			Command:       command,
+			commandChain:  []*Command{command},
+			reexecution:   reexecution,
+			context:       router.rootContext(),
 		}
 
-		// Split the messages at any whitespace
-		parts := regexSplitting.Split(content, -1)
+		// Trigger the command
+		router.dispatch(ctx)
+	}
+}
 
-		// Check if the message starts with a command name
-		for _, command := range router.Commands {
-			// Check if the first part is the current command
-			if !stringArrayContains(getIdentifiers(command), parts[0], command.IgnoreCase) {
-				continue
-			}
-			content = strings.Join(parts[1:], " ")
-
-			// Define the command context
-			ctx := &Ctx{
-				Session:       session,
-				Event:         event,
-				Arguments:     ParseArguments(content),
-				CustomObjects: newObjectsMap(),
-				Router:        router,
-				Command:       command,
-			}
+// rootContext returns the context all command contexts are derived from and creates it if needed
+func (router *Router) rootContext() context.Context {
+	router.rootCtxOnce.Do(func() {
+		router.rootCtx, router.rootCancel = context.WithCancel(context.Background())
+	})
+	return router.rootCtx
+}
 
-			// Trigger the command
-			command.trigger(ctx)
+// indexOfCmd returns the index of the command with the given name or alias or -1 if it doesn't exist
+func indexOfCmd(commands []*Command, name string) int {
+	for index, command := range commands {
+		if stringArrayContains(getIdentifiers(command), name, command.IgnoreCase) {
+			return index
 		}
 	}
+	return -1
 }
 
 func getIdentifiers(command *Command) []string {
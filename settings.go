@@ -0,0 +1,178 @@
+package dgc
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CommandSettingsProvider represents a provider for per-guild command settings
+type CommandSettingsProvider interface {
+	// IsCommandEnabled returns whether or not the command with the given path may be executed in the given guild channel
+	IsCommandEnabled(guildID, channelID, commandPath string) bool
+
+	// SetCommandEnabled enables or disables the command with the given path for the given guild.
+	// If the channel ID is empty, the setting applies to the whole guild.
+	SetCommandEnabled(guildID, channelID, commandPath string, enabled bool) error
+}
+
+// NewInMemoryCommandSettingsProvider creates a new command settings provider which keeps its settings in memory
+func NewInMemoryCommandSettingsProvider() CommandSettingsProvider {
+	return NewStoreCommandSettingsProvider(newObjectsMap())
+}
+
+// commandSettingsStoreName defines the name of the store used by the default command settings command if the router has a storage backend
+const commandSettingsStoreName = "dgc_commandSettings"
+
+// StoreCommandSettingsProvider represents a command settings provider which keeps its settings in a storage map.
+// If the map is bound to a storage backend, the settings survive restarts.
+type StoreCommandSettingsProvider struct {
+	store *ObjectsMap
+}
+
+// NewStoreCommandSettingsProvider creates a new command settings provider keeping its settings in the given storage map
+func NewStoreCommandSettingsProvider(store *ObjectsMap) CommandSettingsProvider {
+	return &StoreCommandSettingsProvider{
+		store: store,
+	}
+}
+
+// IsCommandEnabled returns whether or not the command with the given path may be executed in the given guild channel.
+// A command is disabled if itself or one of its parent commands is disabled, whereby channel settings override guild settings.
+func (provider *StoreCommandSettingsProvider) IsCommandEnabled(guildID, channelID, commandPath string) bool {
+	// Check every command of the path, starting with the top level one
+	names := strings.Fields(commandPath)
+	for index := range names {
+		path := strings.Join(names[:index+1], " ")
+
+		// Check the channel setting first as it overrides the guild setting
+		enabled, ok := provider.store.Get(guildID + ":" + channelID + ":" + path)
+		if !ok {
+			enabled, ok = provider.store.Get(guildID + "::" + path)
+		}
+		if ok && enabled == false {
+			return false
+		}
+	}
+	return true
+}
+
+// SetCommandEnabled enables or disables the command with the given path for the given guild.
+// If the channel ID is empty, the setting applies to the whole guild.
+func (provider *StoreCommandSettingsProvider) SetCommandEnabled(guildID, channelID, commandPath string, enabled bool) error {
+	provider.store.Set(guildID+":"+channelID+":"+strings.Join(strings.Fields(commandPath), " "), enabled)
+	return nil
+}
+
+// RegisterDefaultCommandSettingsCommand registers the default command used to enable or disable commands.
+// It can only be used inside guilds by members with the permission to manage the guild.
+func (router *Router) RegisterDefaultCommandSettingsCommand(rateLimiter RateLimiter) {
+	// Use a persistent provider if the router has a storage backend and the in-memory provider otherwise
+	if router.CommandSettings == nil && router.StorageBackend != nil {
+		router.CommandSettings = NewStoreCommandSettingsProvider(router.Store(commandSettingsStoreName))
+	}
+	if router.CommandSettings == nil {
+		router.CommandSettings = NewInMemoryCommandSettingsProvider()
+	}
+
+	// Register the default command settings command
+	router.RegisterCmd(&Command{
+		Name:        "command",
+		Description: "Enables or disables commands in this server or a specific channel",
+		Usage:       "command <enable|disable> <command name> [channel mention]",
+		Example:     "command disable meme #general",
+		IgnoreCase:  true,
+		Scope:       ScopeGuild,
+		Permissions: discordgo.PermissionManageServer,
+		SubCommands: []*Command{
+			{
+				Name:        "enable",
+				Description: "Enables a command in this server or a specific channel",
+				Usage:       "command enable <command name> [channel mention]",
+				Example:     "command enable meme #memes",
+				IgnoreCase:  true,
+				RateLimiter: rateLimiter,
+				Handler: func(ctx *Ctx) {
+					commandSettingsCommand(ctx, true)
+				},
+			},
+			{
+				Name:        "disable",
+				Description: "Disables a command in this server or a specific channel",
+				Usage:       "command disable <command name> [channel mention]",
+				Example:     "command disable meme #general",
+				IgnoreCase:  true,
+				RateLimiter: rateLimiter,
+				Handler: func(ctx *Ctx) {
+					commandSettingsCommand(ctx, false)
+				},
+			},
+		},
+		RateLimiter: rateLimiter,
+		Handler: func(ctx *Ctx) {
+			ctx.RespondText("Usage: `" + ctx.Router.Prefixes[0] + ctx.Command.Usage + "`")
+		},
+	})
+}
+
+// commandSettingsCommand handles the enable and disable sub commands of the default command settings command
+func commandSettingsCommand(ctx *Ctx, enabled bool) {
+	// Define useful variables
+	guildID := ctx.Event.GuildID
+	router := ctx.Router
+
+	// Define the channel the setting applies to
+	arguments := ctx.Arguments
+	channelID := ""
+	if arguments.Amount() > 1 {
+		channelID = arguments.Get(arguments.Amount() - 1).AsChannelMentionID()
+		if channelID != "" {
+			arguments.Remove(arguments.Amount() - 1)
+		}
+	}
+	if arguments.Amount() == 0 {
+		ctx.RespondText("Usage: `" + router.Prefixes[0] + ctx.Command.Usage + "`")
+		return
+	}
+
+	// Resolve the command path using the primary command names
+	var command *Command
+	path := make([]string, arguments.Amount())
+	for index := 0; index < arguments.Amount(); index++ {
+		name := arguments.Get(index).Raw()
+		if index == 0 {
+			command = router.GetCmd(name)
+		} else {
+			command = command.GetSubCmd(name)
+		}
+		if command == nil {
+			ctx.RespondText("The command `" + arguments.Raw() + "` doesn't exist.")
+			return
+		}
+		path[index] = command.Name
+	}
+
+	// Prevent the settings command from locking itself out
+	if path[0] == ctx.commandChain[0].Name {
+		ctx.RespondText("This command can't be enabled or disabled.")
+		return
+	}
+
+	// Apply the setting
+	err := router.CommandSettings.SetCommandEnabled(guildID, channelID, strings.Join(path, " "), enabled)
+	if err != nil {
+		ctx.RespondText("The setting couldn't be saved: " + err.Error())
+		return
+	}
+
+	// Respond with a confirmation
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+	scope := "this server"
+	if channelID != "" {
+		scope = "<#" + channelID + ">"
+	}
+	ctx.RespondText("The command `" + strings.Join(path, " ") + "` is now " + state + " in " + scope + ".")
+}
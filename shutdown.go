@@ -0,0 +1,147 @@
+package dgc
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// closer represents a component whose resources may be released when the router shuts down
+type closer interface {
+	Close() error
+}
+
+// Shutdown stops accepting new commands, removes the handlers registered by the router and waits for the running commands to return.
+// If the given context expires before, the contexts of the running commands get cancelled and the error of the context is returned.
+// Afterwards, the rate limiters implementing a Close method get closed, which writes the cooldowns of store rate limiters,
+// the modules get unloaded and the storage maps and the storage backend get flushed.
+func (router *Router) Shutdown(ctx context.Context) error {
+	// Stop accepting new commands
+	router.lifecycleMutex.Lock()
+	router.shutDown = true
+	removers := router.handlerRemovers
+	router.handlerRemovers = nil
+	router.lifecycleMutex.Unlock()
+
+	// Remove the registered handlers
+	for _, remove := range removers {
+		remove()
+	}
+	router.removeModuleHandlers()
+	router.eventBus().Close()
+
+	// Wait for the running commands to return
+	var err error
+	done := make(chan struct{})
+	go func() {
+		router.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	// Cancel the contexts of the commands that are still running
+	router.rootContext()
+	router.rootCancel()
+
+	// Release the resources of the router once
+	router.shutdownOnce.Do(func() {
+		if releaseErr := router.releaseResources(err == nil); err == nil {
+			err = releaseErr
+		}
+	})
+	return err
+}
+
+// releaseResources stops the background tasks of the router, closes its rate limiters, unloads its modules and flushes its storage backend.
+// The workers are only stopped if no command is running anymore.
+func (router *Router) releaseResources(idle bool) error {
+	// Stop the workers
+	if idle {
+		router.workersOnce.Do(func() {})
+		if router.queue != nil {
+			close(router.queue)
+		}
+	}
+
+	// Stop cleaning up the tracked responses if they got tracked at all
+	router.responsesOnce.Do(func() {})
+	if router.responses != nil {
+		router.responses.responses.StopCleaner()
+	}
+
+	// Close the rate limiters
+	var err error
+	var walk func(commands []*Command)
+	walk = func(commands []*Command) {
+		for _, command := range commands {
+			if rateLimiter, ok := command.RateLimiter.(closer); ok {
+				if closeErr := rateLimiter.Close(); err == nil {
+					err = closeErr
+				}
+			}
+			walk(command.SubCommands)
+		}
+	}
+	walk(router.commands())
+
+	// Unload the modules
+	for _, name := range router.ModuleNames() {
+		if unloadErr := router.UnloadModule(name); err == nil {
+			err = unloadErr
+		}
+	}
+
+	// Wait for the storage maps to write their modifications and flush the storage backend
+	router.storageMutex.RLock()
+	stores := make([]*ObjectsMap, 0, len(router.Storage))
+	for _, store := range router.Storage {
+		stores = append(stores, store)
+	}
+	router.storageMutex.RUnlock()
+	for _, store := range stores {
+		store.Flush()
+	}
+	if router.StorageBackend != nil {
+		if flushErr := router.StorageBackend.Flush(); err == nil {
+			err = flushErr
+		}
+	}
+	return err
+}
+
+// addHandler registers the given handler to the given session and remembers it to remove it on shutdown
+func (router *Router) addHandler(session *discordgo.Session, handler interface{}) {
+	remove := session.AddHandler(handler)
+
+	router.lifecycleMutex.Lock()
+	defer router.lifecycleMutex.Unlock()
+	router.handlerRemovers = append(router.handlerRemovers, remove)
+}
+
+// beginExecution registers the command of the given context as running and returns false if the router has been shut down
+func (router *Router) beginExecution(ctx *Ctx) bool {
+	router.lifecycleMutex.RLock()
+	defer router.lifecycleMutex.RUnlock()
+
+	if router.shutDown {
+		return false
+	}
+	router.holdExecution(ctx)
+	return true
+}
+
+// holdExecution registers the command of the given context as running another time, for example while it is parked
+func (router *Router) holdExecution(ctx *Ctx) {
+	router.running.Add(1)
+	ctx.reexecution.acquire()
+}
+
+// finishExecution marks one registration of the command of the given context as done
+func (router *Router) finishExecution(ctx *Ctx) {
+	ctx.reexecution.release()
+	router.running.Done()
+}
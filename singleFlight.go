@@ -0,0 +1,91 @@
+package dgc
+
+// SingleFlightMode represents the way a command handles a second invocation by a user whose first one is still running
+type SingleFlightMode int
+
+const (
+	// SingleFlightDisabled allows users to run a command several times at once
+	SingleFlightDisabled SingleFlightMode = iota
+
+	// SingleFlightReject rejects the second invocation and calls the in-flight handler of the router
+	SingleFlightReject
+
+	// SingleFlightQueue runs the second invocation once the first one returned.
+	// Waiting invocations don't occupy a worker and are rejected if too many of them are queued.
+	SingleFlightQueue
+)
+
+// singleFlightQueueLimit defines how many invocations of a command by the same user may wait for the running one.
+// Further invocations are rejected.
+const singleFlightQueueLimit = 3
+
+// inFlightKey represents the key of the running invocations of a command by a specific user
+type inFlightKey struct {
+	command *Command
+	userID  string
+}
+
+// enterInFlightGate calls run once the author of the given context doesn't run the given command anymore.
+// Queued invocations are parked and resumed once the running one returned, so they never block a worker.
+// Rejected invocations call the in-flight handler of the router or tell the user the command is still running if there is none.
+func (router *Router) enterInFlightGate(ctx *Ctx, command *Command, run func(leave func())) {
+	if command.SingleFlight == SingleFlightDisabled {
+		run(func() {})
+		return
+	}
+
+	// Get the gate of the author
+	key := inFlightKey{
+		command: command,
+		userID:  ctx.Event.Author.ID,
+	}
+	router.inFlightMutex.Lock()
+	if router.inFlight == nil {
+		router.inFlight = make(map[inFlightKey]*executionGate)
+	}
+	gate, ok := router.inFlight[key]
+	if !ok {
+		gate = &executionGate{
+			limit: 1,
+		}
+		router.inFlight[key] = gate
+	}
+	leave := func() {
+		router.inFlightMutex.Lock()
+		next := gate.leave()
+		if gate.idle() {
+			delete(router.inFlight, key)
+		}
+		router.inFlightMutex.Unlock()
+		if next != nil {
+			next()
+		}
+	}
+
+	// Enter the gate or queue the invocation
+	maxWaiting := 0
+	if command.SingleFlight == SingleFlightQueue {
+		maxWaiting = singleFlightQueueLimit
+	}
+	router.holdExecution(ctx)
+	result := gate.enter(func() {
+		router.resume(ctx, func() {
+			run(leave)
+		}, leave)
+	}, maxWaiting)
+	router.inFlightMutex.Unlock()
+	if result != gateParked {
+		router.finishExecution(ctx)
+	}
+
+	switch result {
+	case gateEntered:
+		run(leave)
+	case gateFull:
+		if router.InFlightHandler != nil {
+			router.InFlightHandler(ctx)
+		} else {
+			ctx.RespondText("You are already running this command, please wait until it finished.")
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package dgc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrStorageClosed is returned if a closed storage backend gets used
+var ErrStorageClosed = errors.New("the storage backend has been closed")
+
+// StorageBackend represents a persistent storage ObjectsMap namespaces may be bound to
+type StorageBackend interface {
+	Load(namespace string) (map[string][]byte, error)
+	Save(namespace, key string, data []byte) error
+	Delete(namespace, key string) error
+	Drop(namespace string) error
+	Flush() error
+	Close() error
+}
+
+// StorageCodec represents a codec used to serialize the values of a bound ObjectsMap
+type StorageCodec interface {
+	Encode(value interface{}) ([]byte, error)
+	Decode(data []byte) (interface{}, error)
+}
+
+// StorageOptions represents the options used to bind an ObjectsMap to a storage backend
+type StorageOptions struct {
+	Codec        StorageCodec
+	ErrorHandler func(namespace, key string, err error)
+}
+
+// JSONStorageCodec represents a storage codec encoding values as JSON.
+// Values of types registered using RegisterStorageType are decoded into their original type,
+// all other values are decoded the way encoding/json decodes into an interface{}.
+type JSONStorageCodec struct{}
+
+// DefaultStorageCodec defines the codec used if the storage options don't define one
+var DefaultStorageCodec StorageCodec = &JSONStorageCodec{}
+
+// storageTypes holds the types registered using RegisterStorageType
+var storageTypes = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	byName: make(map[string]reflect.Type),
+	byType: make(map[reflect.Type]string),
+}
+
+// RegisterStorageType registers the type of the given sample value under the given name
+// so the JSON storage codec decodes its values into that type again
+func RegisterStorageType(name string, sample interface{}) {
+	storageTypes.Lock()
+	defer storageTypes.Unlock()
+
+	valueType := reflect.TypeOf(sample)
+	storageTypes.byName[name] = valueType
+	storageTypes.byType[valueType] = name
+}
+
+// jsonStorageValue represents a value encoded by the JSON storage codec
+type jsonStorageValue struct {
+	Type  string          `json:"type,omitempty"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Encode encodes the given value as JSON
+func (codec *JSONStorageCodec) Encode(value interface{}) ([]byte, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	storageTypes.RLock()
+	name := storageTypes.byType[reflect.TypeOf(value)]
+	storageTypes.RUnlock()
+	return json.Marshal(&jsonStorageValue{
+		Type:  name,
+		Value: raw,
+	})
+}
+
+// Decode decodes the given JSON data
+func (codec *JSONStorageCodec) Decode(data []byte) (interface{}, error) {
+	stored := new(jsonStorageValue)
+	if err := json.Unmarshal(data, stored); err != nil {
+		return nil, err
+	}
+
+	// Decode values of registered types into their original type
+	storageTypes.RLock()
+	valueType, ok := storageTypes.byName[stored.Type]
+	storageTypes.RUnlock()
+	if stored.Type != "" && !ok {
+		return nil, fmt.Errorf("unknown storage type '%s'", stored.Type)
+	}
+	if ok {
+		value := reflect.New(valueType)
+		if err := json.Unmarshal(stored.Value, value.Interface()); err != nil {
+			return nil, err
+		}
+		return value.Elem().Interface(), nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(stored.Value, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// storageBinding represents the binding of an ObjectsMap to a namespace of a storage backend.
+// Modifications are queued and written by a background goroutine in order, so the map never waits for the backend.
+type storageBinding struct {
+	backend   StorageBackend
+	namespace string
+	options   *StorageOptions
+	mutex     sync.Mutex
+	idle      *sync.Cond
+	pending   []*storageWrite
+	writing   bool
+}
+
+// storageWrite represents a queued modification of a storage backend
+type storageWrite struct {
+	key    string
+	data   []byte
+	delete bool
+	err    error
+}
+
+// newStorageBinding creates a new binding to the given namespace of the given backend
+func newStorageBinding(backend StorageBackend, namespace string, options *StorageOptions) *storageBinding {
+	binding := &storageBinding{
+		backend:   backend,
+		namespace: namespace,
+		options:   options,
+	}
+	binding.idle = sync.NewCond(&binding.mutex)
+	return binding
+}
+
+// save queues persisting the given value and its expiration.
+// The value gets encoded immediately, so it may be modified afterwards.
+func (binding *storageBinding) save(key string, value interface{}, expiration time.Time) {
+	encoded, err := binding.options.Codec.Encode(value)
+	binding.enqueue(&storageWrite{
+		key:  key,
+		data: encodeStoredObject(encoded, expiration),
+		err:  err,
+	})
+}
+
+// delete queues removing the given key from the storage backend
+func (binding *storageBinding) delete(key string) {
+	binding.enqueue(&storageWrite{
+		key:    key,
+		delete: true,
+	})
+}
+
+// enqueue queues the given write and starts the writer if it isn't running yet
+func (binding *storageBinding) enqueue(write *storageWrite) {
+	binding.mutex.Lock()
+	defer binding.mutex.Unlock()
+
+	binding.pending = append(binding.pending, write)
+	if !binding.writing {
+		binding.writing = true
+		go binding.write()
+	}
+}
+
+// write writes the queued modifications until there are none left
+func (binding *storageBinding) write() {
+	for {
+		binding.mutex.Lock()
+		writes := binding.pending
+		binding.pending = nil
+		if len(writes) == 0 {
+			binding.writing = false
+			binding.idle.Broadcast()
+			binding.mutex.Unlock()
+			return
+		}
+		binding.mutex.Unlock()
+
+		for _, write := range writes {
+			err := write.err
+			if err == nil && write.delete {
+				err = binding.backend.Delete(binding.namespace, write.key)
+			} else if err == nil {
+				err = binding.backend.Save(binding.namespace, write.key, write.data)
+			}
+			binding.handleError(write.key, err)
+		}
+	}
+}
+
+// wait waits until all queued modifications have been written
+func (binding *storageBinding) wait() {
+	binding.mutex.Lock()
+	defer binding.mutex.Unlock()
+
+	for binding.writing {
+		binding.idle.Wait()
+	}
+}
+
+// handleError passes the given error to the error handler of the binding if there is one
+func (binding *storageBinding) handleError(key string, err error) {
+	if err != nil && binding.options.ErrorHandler != nil {
+		binding.options.ErrorHandler(binding.namespace, key, err)
+	}
+}
+
+// encodeStoredObject prefixes the given encoded value with its expiration in Unix nanoseconds
+func encodeStoredObject(encoded []byte, expiration time.Time) []byte {
+	data := make([]byte, 8+len(encoded))
+	if !expiration.IsZero() {
+		binary.BigEndian.PutUint64(data, uint64(expiration.UnixNano()))
+	}
+	copy(data[8:], encoded)
+	return data
+}
+
+// decodeStoredObject splits the given data into the encoded value and its expiration
+func decodeStoredObject(data []byte) ([]byte, time.Time, error) {
+	if len(data) < 8 {
+		return nil, time.Time{}, errors.New("the stored object is corrupted")
+	}
+	var expiration time.Time
+	if nanos := binary.BigEndian.Uint64(data); nanos != 0 {
+		expiration = time.Unix(0, int64(nanos))
+	}
+	return data[8:], expiration, nil
+}
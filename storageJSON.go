@@ -0,0 +1,160 @@
+package dgc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONFileStorageBackend represents a storage backend keeping all namespaces in a single JSON file.
+// The file gets rewritten on every modification, which makes it suitable for small amounts of data.
+type JSONFileStorageBackend struct {
+	mutex  sync.Mutex
+	path   string
+	data   map[string]map[string][]byte
+	dirty  bool
+	closed bool
+}
+
+// NewJSONFileStorageBackend creates a new JSON file storage backend and loads the given file if it exists
+func NewJSONFileStorageBackend(path string) (StorageBackend, error) {
+	backend := &JSONFileStorageBackend{
+		path: path,
+		data: make(map[string]map[string][]byte),
+	}
+
+	// Load the existing data
+	raw, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &backend.data); err != nil {
+			return nil, err
+		}
+	}
+	return backend, nil
+}
+
+// Load returns a copy of all values stored in the given namespace
+func (backend *JSONFileStorageBackend) Load(namespace string) (map[string][]byte, error) {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	if backend.closed {
+		return nil, ErrStorageClosed
+	}
+	records := make(map[string][]byte, len(backend.data[namespace]))
+	for key, data := range backend.data[namespace] {
+		records[key] = data
+	}
+	return records, nil
+}
+
+// Save stores the given data under the given key of the given namespace
+func (backend *JSONFileStorageBackend) Save(namespace, key string, data []byte) error {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	if backend.closed {
+		return ErrStorageClosed
+	}
+	if backend.data[namespace] == nil {
+		backend.data[namespace] = make(map[string][]byte)
+	}
+	backend.data[namespace][key] = data
+	backend.dirty = true
+	return backend.write()
+}
+
+// Delete removes the given key from the given namespace
+func (backend *JSONFileStorageBackend) Delete(namespace, key string) error {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	if backend.closed {
+		return ErrStorageClosed
+	}
+	if _, ok := backend.data[namespace][key]; !ok {
+		return nil
+	}
+	delete(backend.data[namespace], key)
+	if len(backend.data[namespace]) == 0 {
+		delete(backend.data, namespace)
+	}
+	backend.dirty = true
+	return backend.write()
+}
+
+// Drop removes the given namespace including all of its values
+func (backend *JSONFileStorageBackend) Drop(namespace string) error {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	if backend.closed {
+		return ErrStorageClosed
+	}
+	if _, ok := backend.data[namespace]; !ok {
+		return nil
+	}
+	delete(backend.data, namespace)
+	backend.dirty = true
+	return backend.write()
+}
+
+// Flush writes the file if a previous write failed
+func (backend *JSONFileStorageBackend) Flush() error {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	if backend.closed {
+		return ErrStorageClosed
+	}
+	return backend.write()
+}
+
+// Close flushes and closes the backend
+func (backend *JSONFileStorageBackend) Close() error {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	if backend.closed {
+		return nil
+	}
+	backend.closed = true
+	return backend.write()
+}
+
+// write atomically replaces the file with the current data if it changed, the mutex has to be locked
+func (backend *JSONFileStorageBackend) write() error {
+	if !backend.dirty {
+		return nil
+	}
+	raw, err := json.Marshal(backend.data)
+	if err != nil {
+		return err
+	}
+
+	// Write a temporary file and replace the actual one with it
+	file, err := ioutil.TempFile(filepath.Dir(backend.path), filepath.Base(backend.path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(raw); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	if err := os.Rename(file.Name(), backend.path); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	backend.dirty = false
+	return nil
+}
@@ -0,0 +1,283 @@
+package dgc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	// keyValueCompactionThreshold defines the amount of outdated records a key/value storage file has to contain to be compacted
+	keyValueCompactionThreshold = 1024
+
+	// keyValueMaxRecordSize defines the size a record may not exceed, larger ones are considered corrupted
+	keyValueMaxRecordSize = 64 << 20
+)
+
+// The operations a key/value storage record may describe
+const (
+	keyValueOperationSave byte = iota + 1
+	keyValueOperationDelete
+	keyValueOperationDrop
+)
+
+// KeyValueStorageBackend represents an embedded storage backend appending every modification to a log file.
+// The log gets replayed when the backend is opened and compacted once it contains enough outdated records.
+// A record that was only partially written, for example because the process crashed, is discarded.
+// If a failed write can't be rolled back, the backend refuses further writes so no record gets appended after the broken one.
+type KeyValueStorageBackend struct {
+	mutex   sync.Mutex
+	path    string
+	file    *os.File
+	data    map[string]map[string][]byte
+	records int
+	closed  bool
+	broken  error
+}
+
+// NewKeyValueStorageBackend opens the key/value storage file at the given path and creates it if it doesn't exist
+func NewKeyValueStorageBackend(path string) (StorageBackend, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	backend := &KeyValueStorageBackend{
+		path: path,
+		file: file,
+		data: make(map[string]map[string][]byte),
+	}
+
+	// Replay the log and discard an incomplete last record
+	offset, err := backend.replay()
+	if err == nil {
+		err = file.Truncate(offset)
+	}
+	if err == nil {
+		_, err = file.Seek(offset, io.SeekStart)
+	}
+	if err == nil {
+		err = backend.compactIfNeeded()
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return backend, nil
+}
+
+// Load returns a copy of all values stored in the given namespace
+func (backend *KeyValueStorageBackend) Load(namespace string) (map[string][]byte, error) {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	if backend.closed {
+		return nil, ErrStorageClosed
+	}
+	records := make(map[string][]byte, len(backend.data[namespace]))
+	for key, data := range backend.data[namespace] {
+		records[key] = data
+	}
+	return records, nil
+}
+
+// Save stores the given data under the given key of the given namespace
+func (backend *KeyValueStorageBackend) Save(namespace, key string, data []byte) error {
+	return backend.append(keyValueOperationSave, namespace, key, data)
+}
+
+// Delete removes the given key from the given namespace
+func (backend *KeyValueStorageBackend) Delete(namespace, key string) error {
+	return backend.append(keyValueOperationDelete, namespace, key, nil)
+}
+
+// Drop removes the given namespace including all of its values
+func (backend *KeyValueStorageBackend) Drop(namespace string) error {
+	return backend.append(keyValueOperationDrop, namespace, "", nil)
+}
+
+// Flush commits the written records to the disk
+func (backend *KeyValueStorageBackend) Flush() error {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	if backend.closed {
+		return ErrStorageClosed
+	}
+	return backend.file.Sync()
+}
+
+// Close flushes and closes the storage file
+func (backend *KeyValueStorageBackend) Close() error {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	if backend.closed {
+		return nil
+	}
+	backend.closed = true
+	if err := backend.file.Sync(); err != nil {
+		backend.file.Close()
+		return err
+	}
+	return backend.file.Close()
+}
+
+// append appends a record describing the given operation to the log and applies it
+func (backend *KeyValueStorageBackend) append(operation byte, namespace, key string, data []byte) error {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	if backend.closed {
+		return ErrStorageClosed
+	}
+	if backend.broken != nil {
+		return backend.broken
+	}
+
+	// Remove a partially written record again, so later records don't get discarded with it when the log is replayed
+	offset, err := backend.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := backend.file.Write(encodeKeyValueRecord(operation, namespace, key, data)); err != nil {
+		if truncateErr := backend.file.Truncate(offset); truncateErr != nil {
+			backend.broken = fmt.Errorf("the storage file couldn't be repaired after a failed write: %w", truncateErr)
+		} else if _, seekErr := backend.file.Seek(offset, io.SeekStart); seekErr != nil {
+			backend.broken = fmt.Errorf("the storage file couldn't be repaired after a failed write: %w", seekErr)
+		}
+		return err
+	}
+	backend.apply(operation, namespace, key, data)
+	return backend.compactIfNeeded()
+}
+
+// apply applies the given operation to the in-memory data, the mutex has to be locked
+func (backend *KeyValueStorageBackend) apply(operation byte, namespace, key string, data []byte) {
+	backend.records++
+	switch operation {
+	case keyValueOperationSave:
+		if backend.data[namespace] == nil {
+			backend.data[namespace] = make(map[string][]byte)
+		}
+		backend.data[namespace][key] = data
+	case keyValueOperationDelete:
+		delete(backend.data[namespace], key)
+		if len(backend.data[namespace]) == 0 {
+			delete(backend.data, namespace)
+		}
+	case keyValueOperationDrop:
+		delete(backend.data, namespace)
+	}
+}
+
+// replay applies all complete records of the log and returns the offset after the last one
+func (backend *KeyValueStorageBackend) replay() (int64, error) {
+	reader := bufio.NewReader(backend.file)
+	offset := int64(0)
+	header := make([]byte, 13)
+	for {
+		// Read the header containing the operation and the lengths of the namespace, key and data
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return offset, ignoreIncompleteRecord(err)
+		}
+		namespaceLength := binary.BigEndian.Uint32(header[1:5])
+		keyLength := binary.BigEndian.Uint32(header[5:9])
+		dataLength := binary.BigEndian.Uint32(header[9:13])
+		if uint64(namespaceLength)+uint64(keyLength)+uint64(dataLength) > keyValueMaxRecordSize {
+			return offset, nil
+		}
+
+		// Read the body and verify its checksum
+		body := make([]byte, int(namespaceLength)+int(keyLength)+int(dataLength)+4)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return offset, ignoreIncompleteRecord(err)
+		}
+		checksum := crc32.NewIEEE()
+		checksum.Write(header)
+		checksum.Write(body[:len(body)-4])
+		if checksum.Sum32() != binary.BigEndian.Uint32(body[len(body)-4:]) {
+			return offset, nil
+		}
+
+		namespace := string(body[:namespaceLength])
+		key := string(body[namespaceLength : namespaceLength+keyLength])
+		data := body[namespaceLength+keyLength : len(body)-4]
+		backend.apply(header[0], namespace, key, data)
+		offset += int64(len(header) + len(body))
+	}
+}
+
+// compactIfNeeded rewrites the log without the outdated records if it contains enough of them, the mutex has to be locked
+func (backend *KeyValueStorageBackend) compactIfNeeded() error {
+	live := 0
+	for _, records := range backend.data {
+		live += len(records)
+	}
+	outdated := backend.records - live
+	if outdated < keyValueCompactionThreshold || outdated < live {
+		return nil
+	}
+
+	// Write the current values to a temporary file
+	temporaryPath := backend.path + ".compact"
+	file, err := os.OpenFile(temporaryPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	for namespace, records := range backend.data {
+		for key, data := range records {
+			if _, err := writer.Write(encodeKeyValueRecord(keyValueOperationSave, namespace, key, data)); err != nil {
+				file.Close()
+				os.Remove(temporaryPath)
+				return err
+			}
+		}
+	}
+	if err := writer.Flush(); err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(temporaryPath)
+		return err
+	}
+
+	// Replace the log with the temporary file
+	if err := os.Rename(temporaryPath, backend.path); err != nil {
+		file.Close()
+		os.Remove(temporaryPath)
+		return err
+	}
+	backend.file.Close()
+	backend.file = file
+	backend.records = live
+	return nil
+}
+
+// encodeKeyValueRecord encodes a record describing the given operation
+func encodeKeyValueRecord(operation byte, namespace, key string, data []byte) []byte {
+	record := make([]byte, 13, 13+len(namespace)+len(key)+len(data)+4)
+	record[0] = operation
+	binary.BigEndian.PutUint32(record[1:5], uint32(len(namespace)))
+	binary.BigEndian.PutUint32(record[5:9], uint32(len(key)))
+	binary.BigEndian.PutUint32(record[9:13], uint32(len(data)))
+	record = append(record, namespace...)
+	record = append(record, key...)
+	record = append(record, data...)
+	checksum := make([]byte, 4)
+	binary.BigEndian.PutUint32(checksum, crc32.ChecksumIEEE(record))
+	return append(record, checksum...)
+}
+
+// ignoreIncompleteRecord ignores the errors caused by reaching the end of the log
+func ignoreIncompleteRecord(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil
+	}
+	return err
+}
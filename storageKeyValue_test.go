@@ -0,0 +1,109 @@
+package dgc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// openTestKeyValueBackend opens a key/value storage backend in a new temporary directory
+func openTestKeyValueBackend(t *testing.T) (*KeyValueStorageBackend, string) {
+	directory, err := ioutil.TempDir("", "dgc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(directory)
+	})
+	path := filepath.Join(directory, "storage.db")
+	backend, err := NewKeyValueStorageBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return backend.(*KeyValueStorageBackend), path
+}
+
+// reopenTestKeyValueBackend closes the given backend and opens the file at the given path again
+func reopenTestKeyValueBackend(t *testing.T, backend StorageBackend, path string) StorageBackend {
+	if err := backend.Close(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewKeyValueStorageBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		reopened.Close()
+	})
+	return reopened
+}
+
+// assertStoredKeys checks that the given namespace contains exactly the given keys
+func assertStoredKeys(t *testing.T, backend StorageBackend, namespace string, keys ...string) {
+	records, err := backend.Load(namespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(keys) {
+		t.Fatalf("expected the keys %v, got %d records", keys, len(records))
+	}
+	for _, key := range keys {
+		if _, ok := records[key]; !ok {
+			t.Fatalf("the key '%s' is missing", key)
+		}
+	}
+}
+
+func TestKeyValueStorageBackendReplay(t *testing.T) {
+	backend, path := openTestKeyValueBackend(t)
+	backend.Save("namespace", "k1", []byte("v1"))
+	backend.Save("namespace", "k2", []byte("v2"))
+	backend.Delete("namespace", "k1")
+	backend.Save("dropped", "k", []byte("v"))
+	backend.Drop("dropped")
+
+	reopened := reopenTestKeyValueBackend(t, backend, path)
+	assertStoredKeys(t, reopened, "namespace", "k2")
+	assertStoredKeys(t, reopened, "dropped")
+}
+
+func TestKeyValueStorageBackendDiscardsIncompleteRecord(t *testing.T) {
+	backend, path := openTestKeyValueBackend(t)
+	backend.Save("namespace", "k1", []byte("v1"))
+	record := encodeKeyValueRecord(keyValueOperationSave, "namespace", "k2", []byte("v2"))
+	backend.file.Write(record[:len(record)-3])
+
+	// The incomplete record gets truncated, so records written after reopening are kept
+	reopened := reopenTestKeyValueBackend(t, backend, path)
+	assertStoredKeys(t, reopened, "namespace", "k1")
+	if err := reopened.Save("namespace", "k3", []byte("v3")); err != nil {
+		t.Fatal(err)
+	}
+	reopened = reopenTestKeyValueBackend(t, reopened, path)
+	assertStoredKeys(t, reopened, "namespace", "k1", "k3")
+}
+
+func TestKeyValueStorageBackendRefusesWritesAfterUnrepairableFailure(t *testing.T) {
+	backend, path := openTestKeyValueBackend(t)
+	backend.Save("namespace", "k1", []byte("v1"))
+
+	// Let the next write fail without being able to truncate the file
+	file := backend.file
+	readOnly, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.file = readOnly
+	if err := backend.Save("namespace", "k2", []byte("v2")); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	backend.file = file
+	readOnly.Close()
+	if err := backend.Save("namespace", "k3", []byte("v3")); err == nil {
+		t.Fatal("expected the backend to refuse further writes")
+	}
+
+	reopened := reopenTestKeyValueBackend(t, backend, path)
+	assertStoredKeys(t, reopened, "namespace", "k1")
+}
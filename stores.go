@@ -0,0 +1,93 @@
+package dgc
+
+import "sort"
+
+// Store returns the storage map with the given name and creates it if it doesn't exist yet.
+// If the router has a storage backend, a newly created map gets bound to the namespace with the given name;
+// errors occurring while loading it are passed to the error handler of the storage options.
+func (router *Router) Store(name string) *ObjectsMap {
+	router.storageMutex.Lock()
+	defer router.storageMutex.Unlock()
+
+	if storage, ok := router.Storage[name]; ok {
+		return storage
+	}
+	storage, err := router.createStore(name)
+	if err != nil && router.StorageOptions != nil && router.StorageOptions.ErrorHandler != nil {
+		router.StorageOptions.ErrorHandler(name, "", err)
+	}
+	return storage
+}
+
+// StoreNames returns the sorted names of all existing storage maps
+func (router *Router) StoreNames() []string {
+	router.storageMutex.RLock()
+	defer router.storageMutex.RUnlock()
+
+	names := make([]string, 0, len(router.Storage))
+	for name := range router.Storage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DropStore removes the storage map with the given name including its values in the storage backend.
+// The dropped map gets detached from the storage backend, so references kept to it only modify it in memory.
+func (router *Router) DropStore(name string) error {
+	router.storageMutex.Lock()
+	storage, ok := router.Storage[name]
+	delete(router.Storage, name)
+	router.storageMutex.Unlock()
+
+	// Wait for the queued modifications so they don't restore the namespace after dropping it
+	if ok {
+		storage.unbind()
+	}
+	if router.StorageBackend != nil {
+		return router.StorageBackend.Drop(name)
+	}
+	return nil
+}
+
+// createStore creates a new storage map with the given name and binds it to the storage backend if there is one.
+// The map is registered even if binding it fails. The storage mutex has to be locked.
+func (router *Router) createStore(name string) (*ObjectsMap, error) {
+	if router.Storage == nil {
+		router.Storage = make(map[string]*ObjectsMap)
+	}
+	storage := newObjectsMap()
+	router.Storage[name] = storage
+	if router.StorageBackend != nil {
+		return storage, storage.Bind(router.StorageBackend, name, router.StorageOptions)
+	}
+	return storage, nil
+}
+
+// The prefixes of the names of the storage maps scoped to a specific guild, user or channel
+const (
+	guildStorePrefix   = "dgc_guild:"
+	userStorePrefix    = "dgc_user:"
+	channelStorePrefix = "dgc_channel:"
+)
+
+// GuildStore returns the storage map of the guild the command was executed in.
+// Commands executed in direct messages get a new map which is neither persisted nor shared.
+func (ctx *Ctx) GuildStore() *ObjectsMap {
+	if ctx.Event.GuildID == "" {
+		return newObjectsMap()
+	}
+	return ctx.Router.Store(guildStorePrefix + ctx.Event.GuildID)
+}
+
+// UserStore returns the storage map of the user who executed the command.
+// The map is kept until it gets removed using DropStore.
+func (ctx *Ctx) UserStore() *ObjectsMap {
+	return ctx.Router.Store(userStorePrefix + ctx.Event.Author.ID)
+}
+
+// ChannelStore returns the storage map of the channel the command was executed in.
+// The map is kept until it gets removed using DropStore.
+func (ctx *Ctx) ChannelStore() *ObjectsMap {
+	return ctx.Router.Store(channelStorePrefix + ctx.Event.ChannelID)
+}
@@ -0,0 +1,209 @@
+package dgc
+
+// OverflowPolicy represents the way commands are handled if the worker pool queue or the concurrency limit of a command is exhausted
+type OverflowPolicy int
+
+const (
+	// OverflowPolicyBlock waits until the command may be executed.
+	// Commands waiting for their concurrency limit are parked and don't occupy a worker.
+	OverflowPolicyBlock OverflowPolicy = iota
+
+	// OverflowPolicyDrop silently drops the command
+	OverflowPolicyDrop
+
+	// OverflowPolicyReplyBusy drops the command and calls the busy handler of the router or tells the user the bot is busy if there is none
+	OverflowPolicyReplyBusy
+)
+
+// dispatch triggers the command of the given context directly or queues it into the worker pool if the router uses one
+func (router *Router) dispatch(ctx *Ctx) {
+	if !router.beginExecution(ctx) {
+		return
+	}
+	if router.Workers <= 0 {
+		router.execute(ctx)
+		return
+	}
+
+	// Start the workers
+	router.workersOnce.Do(func() {
+		router.queue = make(chan func(), router.QueueSize)
+		for i := 0; i < router.Workers; i++ {
+			go router.work()
+		}
+	})
+
+	// Queue the command
+	job := func() {
+		router.execute(ctx)
+	}
+	select {
+	case router.queue <- job:
+		return
+	default:
+	}
+	if router.overflow(ctx) {
+		router.queue <- job
+		return
+	}
+	router.finishExecution(ctx)
+}
+
+// work runs the queued jobs
+func (router *Router) work() {
+	for job := range router.queue {
+		job()
+	}
+}
+
+// execute triggers the command of the given context and marks it as done afterwards
+func (router *Router) execute(ctx *Ctx) {
+	defer router.finishExecution(ctx)
+	ctx.Command.trigger(ctx)
+}
+
+// overflow handles a command that can't be executed right now according to the overflow policy.
+// It returns whether or not the caller should wait until the command may be executed.
+func (router *Router) overflow(ctx *Ctx) bool {
+	switch router.OverflowPolicy {
+	case OverflowPolicyDrop:
+		return false
+	case OverflowPolicyReplyBusy:
+		if router.BusyHandler != nil {
+			router.BusyHandler(ctx)
+		} else {
+			ctx.RespondText("The bot is busy right now, please try again later.")
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// enterConcurrencyGate calls run once the given command has a free execution slot if it limits its concurrency.
+// If there is none, the execution is parked according to the overflow policy and resumed once a slot gets handed over to it,
+// so it never blocks a worker. If the execution gets dropped, rejected is called instead.
+func (router *Router) enterConcurrencyGate(ctx *Ctx, command *Command, run func(leave func()), rejected func()) {
+	if command.MaxConcurrency <= 0 {
+		run(func() {})
+		return
+	}
+
+	// Get the gate of the command
+	router.slotsMutex.Lock()
+	if router.slots == nil {
+		router.slots = make(map[*Command]*executionGate)
+	}
+	gate, ok := router.slots[command]
+	if !ok {
+		gate = &executionGate{
+			limit: command.MaxConcurrency,
+		}
+		router.slots[command] = gate
+	}
+	leave := func() {
+		router.slotsMutex.Lock()
+		next := gate.leave()
+		if gate.idle() {
+			delete(router.slots, command)
+		}
+		router.slotsMutex.Unlock()
+		if next != nil {
+			next()
+		}
+	}
+
+	// Enter the gate or park the execution
+	maxWaiting := 0
+	if router.OverflowPolicy == OverflowPolicyBlock {
+		maxWaiting = -1
+	}
+	router.holdExecution(ctx)
+	result := gate.enter(func() {
+		router.resume(ctx, func() {
+			run(leave)
+		}, leave)
+	}, maxWaiting)
+	router.slotsMutex.Unlock()
+	if result != gateParked {
+		router.finishExecution(ctx)
+	}
+
+	switch result {
+	case gateEntered:
+		run(leave)
+	case gateFull:
+		router.overflow(ctx)
+		rejected()
+	}
+}
+
+// resume runs the given parked execution on the worker pool without blocking the caller.
+// If the context of the execution got cancelled in the meantime, leave is called instead.
+// The execution has to be registered as running when it got parked.
+func (router *Router) resume(ctx *Ctx, run func(), leave func()) {
+	job := func() {
+		defer router.finishExecution(ctx)
+		if ctx.Context().Err() != nil {
+			leave()
+			return
+		}
+		run()
+	}
+	if router.Workers <= 0 {
+		go job()
+		return
+	}
+	go func() {
+		router.queue <- job
+	}()
+}
+
+// gateResult represents the result of entering an execution gate
+type gateResult int
+
+const (
+	gateEntered gateResult = iota
+	gateParked
+	gateFull
+)
+
+// executionGate limits the amount of executions running at once.
+// Executions waiting for a free slot are parked instead of blocking their goroutine.
+// The lock guarding a gate has to be held while using it.
+type executionGate struct {
+	limit   int
+	running int
+	waiting []func()
+}
+
+// enter acquires a free slot or parks the given resume function if less than maxWaiting executions are waiting already.
+// A negative maxWaiting allows any amount of waiting executions. Once a slot gets handed over, resume is called.
+func (gate *executionGate) enter(resume func(), maxWaiting int) gateResult {
+	if gate.running < gate.limit {
+		gate.running++
+		return gateEntered
+	}
+	if maxWaiting >= 0 && len(gate.waiting) >= maxWaiting {
+		return gateFull
+	}
+	gate.waiting = append(gate.waiting, resume)
+	return gateParked
+}
+
+// leave releases a slot or hands it over to the next waiting execution.
+// It returns the resume function of that execution, which has to be called after releasing the lock.
+func (gate *executionGate) leave() func() {
+	if len(gate.waiting) > 0 {
+		next := gate.waiting[0]
+		gate.waiting = gate.waiting[1:]
+		return next
+	}
+	gate.running--
+	return nil
+}
+
+// idle checks whether or not no execution is running or waiting
+func (gate *executionGate) idle() bool {
+	return gate.running == 0 && len(gate.waiting) == 0
+}